@@ -0,0 +1,38 @@
+package main
+
+import "fmt"
+
+// Hold sets or clears the HoldUpdate flag for a tool, so Upgrade.Plan skips it
+type Hold struct {
+	State    *StateManager
+	Reporter Reporter
+
+	Name  string
+	Unset bool
+}
+
+// Run applies the hold/unhold
+func (a *Hold) Run() error {
+	release, key, exists := a.State.GetByName(a.Name)
+	if !exists {
+		return fmt.Errorf("tool %s not found", a.Name)
+	}
+
+	if a.Unset {
+		release.HoldUpdate = false
+		a.reporter().Info(fmt.Sprintf("Unheld updates for %s", a.Name))
+	} else {
+		release.HoldUpdate = true
+		a.reporter().Info(fmt.Sprintf("Held updates for %s", a.Name))
+	}
+
+	a.State.SetItem(key, release)
+	return nil
+}
+
+func (a *Hold) reporter() Reporter {
+	if a.Reporter != nil {
+		return a.Reporter
+	}
+	return StdoutReporter{}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Install resolves, downloads, verifies and installs a single release. It
+// holds no cobra/terminal state so getCmd, pullCmd, and any future caller
+// can all drive the same logic.
+type Install struct {
+	Config   *ConfigManager
+	State    *StateManager
+	Reporter Reporter
+
+	URL      string
+	Name     string
+	TagName  string
+	NoVerify bool
+	Pick     string
+	Exclude  []string
+	OS       string
+	Arch     string
+	Libc     string
+
+	// Path overrides Config.GetPath() for this install, e.g. a per-tool
+	// `path:` entry in a tools manifest
+	Path string
+
+	// Spec selects which file(s) in the extracted archive to install,
+	// for packages shipping more than one binary. Left empty, Run falls
+	// back to the FindExecutable heuristic and installs the single
+	// result under toolName, as before.
+	Spec BinarySpec
+
+	// HistoryNote overrides the auto-generated description recorded in
+	// History for this install (e.g. Rollback sets "rolled back to vX");
+	// left empty, Run derives one from whether a previous release existed
+	HistoryNote string
+}
+
+// InstallResult describes what Install.Run did
+type InstallResult struct {
+	ToolName string
+	Release  *Release
+	Asset    *ReleaseAssets
+}
+
+// Resolve fetches repository metadata and resolves the asset to install,
+// without downloading or installing anything. Callers that want to show a
+// preview/confirmation prompt (like getCmd) call Resolve first, then pass
+// its result to Run; callers that don't (like pullCmd) can skip straight to
+// Run with a nil releases/asset pair, which resolves lazily.
+func (a *Install) Resolve() (*RepositoryInfo, []*Release, *ReleaseAssets, error) {
+	repo, err := GetRepoInfo(a.URL, a.Config.GetConfig())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting repository info: %v", err)
+	}
+
+	repoInfo, err := repo.Repository()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting repository: %v", err)
+	}
+
+	release, err := resolveRelease(repo, a.TagName, a.Config.GetPreRelease())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error getting releases: %v", err)
+	}
+	releases := []*Release{release}
+
+	override := mergeSelectorOverrides(
+		repoOverrideSelector(a.Config.GetAssetOverride(a.URL)),
+		&AssetSelector{Pick: a.Pick, MustNotMatch: a.Exclude, OS: a.OS, Arch: a.Arch, Libc: a.Libc},
+	)
+	selector := NewAssetSelector(a.Config.GetConfig(), override)
+	asset, err := GetReleaseWithSelector(releases, selector)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error finding release: %v", err)
+	}
+
+	return repoInfo, releases, asset, nil
+}
+
+// Run downloads, verifies and installs releases[0]/asset (as returned by
+// Resolve), then records the result in state. If releases or asset is nil,
+// Run resolves them itself.
+func (a *Install) Run(releases []*Release, asset *ReleaseAssets) (*InstallResult, error) {
+	reporter := a.reporter()
+
+	if releases == nil || asset == nil {
+		var err error
+		_, releases, asset, err = a.Resolve()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	toolName := a.Name
+	if toolName == "" {
+		toolName = toolNameFromURL(a.URL)
+	}
+
+	tempDir := filepath.Join(GetTempDir(), "install-release", "extract", toolName)
+	if err := Mkdir(tempDir); err != nil {
+		return nil, fmt.Errorf("error creating temp directory: %v", err)
+	}
+	defer RemoveDir(tempDir)
+
+	previous, _, hadPrevious := a.State.GetByName(toolName)
+
+	event, previousHooks, oldTag := HookPreInstall, (*HookSet)(nil), ""
+	if hadPrevious {
+		event = HookPreUpgrade
+		previousHooks = previous.Hooks
+		oldTag = previous.TagName
+	}
+	hookEnv := HookEnv(toolName, oldTag, releases[0].TagName, "")
+	if err := RunHooks(ResolveHooks(a.Config, previousHooks), event, hookEnv); err != nil {
+		return nil, err
+	}
+
+	reporter.Info(fmt.Sprintf("Downloading: %s (%.1f MB)", asset.Name, asset.SizeMB()))
+	verifyOpts := ResolveVerifyOptions(a.Config, a.URL, a.NoVerify)
+	digest, err := ExtractRelease(asset, releases[0], tempDir, verifyOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting release: %v", err)
+	}
+
+	if hadPrevious {
+		if err := CheckTamper(previous, releases[0].TagName, digest); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved, err := ResolveBinaries(tempDir, a.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving binaries: %v", err)
+	}
+
+	installPath := a.Config.GetPath()
+	if a.Path != "" {
+		installPath = a.Path
+	}
+
+	var destPath string
+	var installedNames []string
+	for _, bin := range resolved {
+		name := NormalizeBinaryName(bin.Name)
+		if len(resolved) == 1 {
+			// Single-binary packages keep installing under the requested tool name
+			name = toolName
+		}
+		binDestPath := filepath.Join(installPath, name+ExecutableExt())
+		if err := InstallBin(bin.SourcePath, binDestPath, false, name); err != nil {
+			return nil, fmt.Errorf("error installing binary %s: %v", name, err)
+		}
+		installedNames = append(installedNames, name)
+		destPath = binDestPath
+	}
+
+	var auxiliaryPaths []string
+	if auxFiles, err := ScanAuxiliaryFiles(tempDir); err == nil && len(auxFiles) > 0 {
+		if installedAux, err := InstallAuxiliaryFiles(auxFiles); err != nil {
+			reporter.Error(fmt.Sprintf("error installing completions/man pages: %v", err))
+		} else {
+			auxiliaryPaths = installedAux
+		}
+	}
+	if shimPath := EnsureBinOnPath(reporter, installPath, destPath, toolName); shimPath != "" {
+		auxiliaryPaths = append(auxiliaryPaths, shimPath)
+	}
+
+	releases[0].Assets = []ReleaseAssets{*asset}
+	releases[0].Binaries = installedNames
+	releases[0].AuxiliaryPaths = auxiliaryPaths
+	if a.Pick != "" || len(a.Exclude) > 0 || a.OS != "" || a.Arch != "" || a.Libc != "" {
+		releases[0].Selector = &AssetSelector{Pick: a.Pick, MustNotMatch: a.Exclude, OS: a.OS, Arch: a.Arch, Libc: a.Libc}
+	}
+	releases[0].VerifiedDigest = digest
+	releases[0].Uninstalled = false
+
+	note := a.HistoryNote
+	if note == "" {
+		switch {
+		case hadPrevious && previous.TagName != releases[0].TagName:
+			note = fmt.Sprintf("upgraded from %s to %s", previous.TagName, releases[0].TagName)
+		case hadPrevious:
+			note = fmt.Sprintf("reinstalled %s", releases[0].TagName)
+		default:
+			note = "initial install"
+		}
+	}
+	if hadPrevious {
+		releases[0].History = previous.History
+		releases[0].Hooks = previous.Hooks
+	}
+	AppendHistory(releases[0], note)
+
+	a.State.SetByName(a.URL, toolName, releases[0])
+
+	postEvent := HookPostInstall
+	if hadPrevious {
+		postEvent = HookPostUpgrade
+	}
+	hookEnv["IR_ASSET_PATH"] = destPath
+	if err := RunHooks(ResolveHooks(a.Config, releases[0].Hooks), postEvent, hookEnv); err != nil {
+		reporter.Error(err.Error())
+	}
+	NotifyEvent(a.Config, postEvent, hookEnv)
+
+	reporter.Success(fmt.Sprintf("Installed: %s", toolName))
+	return &InstallResult{ToolName: toolName, Release: releases[0], Asset: asset}, nil
+}
+
+func (a *Install) reporter() Reporter {
+	if a.Reporter != nil {
+		return a.Reporter
+	}
+	return StdoutReporter{}
+}
+
+// toolNameFromURL derives a tool name from a repository URL, e.g.
+// https://github.com/owner/repo -> repo
+func toolNameFromURL(url string) string {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	if len(parts) >= 2 {
+		return parts[len(parts)-1]
+	}
+	return "unknown"
+}
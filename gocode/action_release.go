@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ReleaseCreate publishes a new release and uploads its assets, backing
+// `ir release create`. It holds no cobra/terminal state, matching the
+// Install/Upgrade/Remove/Hold/Rollback action layer.
+type ReleaseCreate struct {
+	Config   *ConfigManager
+	Reporter Reporter
+
+	RepoURL string
+	Options ReleaseCreateOptions
+	// Assets holds raw `--asset path[#label]` arguments, parsed and uploaded
+	// in order after the release itself is created
+	Assets []string
+}
+
+// Run creates the release, then uploads each asset in turn
+func (a *ReleaseCreate) Run() (*Release, error) {
+	reporter := a.reporter()
+
+	publisher, err := GetReleasePublisher(a.RepoURL, a.Config.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := publisher.CreateRelease(a.Options)
+	if err != nil {
+		return nil, fmt.Errorf("error creating release: %v", err)
+	}
+	reporter.Success(fmt.Sprintf("Created release %s", release.TagName))
+
+	for _, arg := range a.Assets {
+		path, label := ParseReleaseAsset(arg)
+		reporter.Info(fmt.Sprintf("Uploading asset: %s", path))
+		asset, err := publisher.UploadAsset(release.TagName, path, label)
+		if err != nil {
+			return nil, fmt.Errorf("error uploading asset %s: %v", path, err)
+		}
+		release.Assets = append(release.Assets, *asset)
+	}
+
+	return release, nil
+}
+
+func (a *ReleaseCreate) reporter() Reporter {
+	if a.Reporter != nil {
+		return a.Reporter
+	}
+	return StdoutReporter{}
+}
+
+// DownloadReleaseAssets downloads every asset of release matching pattern (a
+// filepath.Match glob, or every asset if empty) into dir, backing
+// `ir release download`
+func DownloadReleaseAssets(release *Release, pattern, dir string, reporter Reporter) error {
+	if err := Mkdir(dir); err != nil {
+		return fmt.Errorf("error creating output directory: %v", err)
+	}
+
+	var matched int
+	for _, asset := range release.Assets {
+		if pattern != "" {
+			ok, err := filepath.Match(pattern, asset.Name)
+			if err != nil {
+				return fmt.Errorf("invalid --pattern %q: %v", pattern, err)
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		matched++
+		dest := filepath.Join(dir, asset.Name)
+		reporter.Info(fmt.Sprintf("Downloading: %s", asset.Name))
+		if err := Download(asset.BrowserDownloadURL, dest); err != nil {
+			return fmt.Errorf("error downloading %s: %v", asset.Name, err)
+		}
+	}
+
+	if matched == 0 {
+		return fmt.Errorf("no assets matched")
+	}
+
+	reporter.Success(fmt.Sprintf("Downloaded %d asset(s) to %s", matched, dir))
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Remove uninstalls a previously installed tool. By default the state entry
+// is kept and marked Uninstalled, with a final "uninstalled" History entry,
+// so `ir history`/`ir rollback` still work after a removal; Purge drops the
+// state entry (and its history) entirely instead.
+type Remove struct {
+	Config   *ConfigManager
+	State    *StateManager
+	Reporter Reporter
+
+	Name  string
+	Purge bool
+}
+
+// Run removes the tool's executable, then tombstones or purges its state entry
+func (a *Remove) Run() error {
+	release, key, found := a.State.GetByName(a.Name)
+	if !found {
+		return fmt.Errorf("tool %s not found", a.Name)
+	}
+
+	binaries := release.Binaries
+	if len(binaries) == 0 {
+		binaries = []string{a.Name}
+	}
+
+	executablePath := filepath.Join(a.Config.GetPath(), a.Name+ExecutableExt())
+	hooks := ResolveHooks(a.Config, release.Hooks)
+	hookEnv := HookEnv(a.Name, release.TagName, "", executablePath)
+	if err := RunHooks(hooks, HookPreRemove, hookEnv); err != nil {
+		return err
+	}
+
+	for _, name := range binaries {
+		path := filepath.Join(a.Config.GetPath(), name+ExecutableExt())
+		if Exists(path) {
+			if err := RemoveFile(path); err != nil {
+				return fmt.Errorf("error removing executable %s: %v", name, err)
+			}
+		}
+	}
+
+	for _, path := range release.AuxiliaryPaths {
+		if Exists(path) {
+			if err := RemoveFile(path); err != nil {
+				a.reporter().Error(fmt.Sprintf("error removing %s: %v", path, err))
+			}
+		}
+	}
+
+	if a.Purge {
+		a.State.DelItem(key)
+		if err := RunHooks(hooks, HookPostRemove, hookEnv); err != nil {
+			a.reporter().Error(err.Error())
+		}
+		NotifyEvent(a.Config, HookPostRemove, hookEnv)
+		a.reporter().Success("Removed: " + a.Name)
+		return nil
+	}
+
+	release.Uninstalled = true
+	AppendHistory(release, "uninstalled")
+	a.State.SetItem(key, release)
+
+	if err := RunHooks(hooks, HookPostRemove, hookEnv); err != nil {
+		a.reporter().Error(err.Error())
+	}
+	NotifyEvent(a.Config, HookPostRemove, hookEnv)
+
+	a.reporter().Success("Removed: " + a.Name)
+	return nil
+}
+
+func (a *Remove) reporter() Reporter {
+	if a.Reporter != nil {
+		return a.Reporter
+	}
+	return StdoutReporter{}
+}
@@ -0,0 +1,89 @@
+package main
+
+import "fmt"
+
+// Rollback reinstalls a tool at a prior tag recorded in its History. It
+// prefers re-downloading the asset cached in that History entry so a
+// rollback doesn't need to re-resolve the release through the GitHub/GitLab
+// API, falling back to a normal resolve if that asset has since been removed.
+type Rollback struct {
+	Config   *ConfigManager
+	State    *StateManager
+	Reporter Reporter
+
+	Name     string
+	ToTag    string
+	NoVerify bool
+}
+
+// Run performs the rollback and records it as a new History entry
+func (a *Rollback) Run() (*InstallResult, error) {
+	reporter := a.reporter()
+
+	release, _, found := a.State.GetByName(a.Name)
+	if !found {
+		return nil, fmt.Errorf("tool %s not found", a.Name)
+	}
+
+	target, targetTag, err := selectRollbackTarget(release, a.ToTag)
+	if err != nil {
+		return nil, err
+	}
+
+	install := &Install{
+		Config:      a.Config,
+		State:       a.State,
+		Reporter:    reporter,
+		URL:         release.URL,
+		Name:        a.Name,
+		TagName:     targetTag,
+		NoVerify:    a.NoVerify,
+		HistoryNote: fmt.Sprintf("rolled back to %s", targetTag),
+	}
+
+	if len(target.Assets) > 0 {
+		cached := &Release{URL: release.URL, TagName: targetTag, Assets: target.Assets}
+		result, err := install.Run([]*Release{cached}, &target.Assets[0])
+		if err == nil {
+			reporter.Success(fmt.Sprintf("Rolled back %s to %s", a.Name, targetTag))
+			return result, nil
+		}
+		reporter.Info(fmt.Sprintf("Cached asset for %s unavailable (%v), re-resolving from %s", targetTag, err, release.URL))
+	}
+
+	result, err := install.Run(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error rolling back %s: %v", a.Name, err)
+	}
+
+	reporter.Success(fmt.Sprintf("Rolled back %s to %s", a.Name, targetTag))
+	return result, nil
+}
+
+// selectRollbackTarget finds the History entry to roll back to: the one
+// matching toTag if given, or else the most recent entry with a different
+// tag than the one currently installed
+func selectRollbackTarget(release *Release, toTag string) (*HistoryRevision, string, error) {
+	if toTag != "" {
+		for i := len(release.History) - 1; i >= 0; i-- {
+			if release.History[i].TagName == toTag {
+				return &release.History[i], toTag, nil
+			}
+		}
+		return nil, "", fmt.Errorf("%s has no recorded history for tag %s", release.Name, toTag)
+	}
+
+	for i := len(release.History) - 1; i >= 0; i-- {
+		if release.History[i].TagName != release.TagName {
+			return &release.History[i], release.History[i].TagName, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no prior version to roll back to")
+}
+
+func (a *Rollback) reporter() Reporter {
+	if a.Reporter != nil {
+		return a.Reporter
+	}
+	return StdoutReporter{}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Upgrade checks every installed, non-held tool for a newer release and
+// installs the ones the caller selects. Plan and Apply are split so a cobra
+// command (or any other caller) can show a confirmation prompt between them.
+type Upgrade struct {
+	Config   *ConfigManager
+	State    *StateManager
+	Reporter Reporter
+
+	Force    bool
+	NoVerify bool
+	OS       string
+	Arch     string
+	Libc     string
+}
+
+// UpgradeCandidate describes a single tool with a newer release available
+type UpgradeCandidate struct {
+	Key              string
+	ToolName         string
+	CurrentVersion   string
+	NewVersion       string
+	Release          *Release
+	Asset            *ReleaseAssets
+	RepoURL          string
+	PreviousDigest   string
+	PreviousHistory  []HistoryRevision
+	PreviousHooks    *HookSet
+	PreviousBinaries []string
+}
+
+// Plan checks every installed, non-held tool concurrently (max 5 at a time)
+// and returns the ones that have an upgrade available
+func (a *Upgrade) Plan() []UpgradeCandidate {
+	items := a.State.Items()
+
+	const maxConcurrent = 5
+	semaphore := make(chan struct{}, maxConcurrent)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var candidates []UpgradeCandidate
+
+	for key, release := range items {
+		if release.HoldUpdate || release.Uninstalled {
+			continue
+		}
+
+		toolName := key
+		if idx := strings.LastIndex(key, "#"); idx != -1 {
+			toolName = key[idx+1:]
+		}
+
+		wg.Add(1)
+		go func(key string, release *Release, toolName string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			a.reporter().Info(fmt.Sprintf("Fetching: %s", key))
+
+			repo, err := GetRepoInfo(release.URL, a.Config.GetConfig())
+			if err != nil {
+				return
+			}
+
+			latestRelease, err := repo.LatestRelease(a.Config.GetPreRelease())
+			if err != nil {
+				return
+			}
+			if latestRelease.TagName == release.TagName && !a.Force {
+				return
+			}
+
+			persisted := mergeSelectorOverrides(release.Selector, &AssetSelector{OS: a.OS, Arch: a.Arch, Libc: a.Libc})
+			override := mergeSelectorOverrides(repoOverrideSelector(a.Config.GetAssetOverride(release.URL)), persisted)
+			selector := NewAssetSelector(a.Config.GetConfig(), override)
+			asset, err := GetReleaseWithSelector([]*Release{latestRelease}, selector)
+			if err != nil {
+				return
+			}
+			latestRelease.Selector = persisted
+
+			mu.Lock()
+			candidates = append(candidates, UpgradeCandidate{
+				Key:              key,
+				ToolName:         toolName,
+				CurrentVersion:   release.TagName,
+				NewVersion:       latestRelease.TagName,
+				Release:          latestRelease,
+				Asset:            asset,
+				RepoURL:          release.URL,
+				PreviousDigest:   release.VerifiedDigest,
+				PreviousHistory:  release.History,
+				PreviousHooks:    release.Hooks,
+				PreviousBinaries: release.Binaries,
+			})
+			mu.Unlock()
+		}(key, release, toolName)
+	}
+
+	wg.Wait()
+	return candidates
+}
+
+// Apply installs each candidate in turn, updating state as it goes.
+// Failures are reported but don't stop the remaining candidates.
+func (a *Upgrade) Apply(candidates []UpgradeCandidate) {
+	reporter := a.reporter()
+
+	for _, candidate := range candidates {
+		reporter.Info(fmt.Sprintf("Updating: %s, %s => %s", candidate.ToolName, candidate.CurrentVersion, candidate.NewVersion))
+
+		hookEnv := HookEnv(candidate.ToolName, candidate.CurrentVersion, candidate.NewVersion, "")
+		if err := RunHooks(ResolveHooks(a.Config, candidate.PreviousHooks), HookPreUpgrade, hookEnv); err != nil {
+			reporter.Error(err.Error())
+			continue
+		}
+
+		tempDir := filepath.Join(GetTempDir(), "install-release", "extract", candidate.ToolName)
+		if err := Mkdir(tempDir); err != nil {
+			reporter.Error(fmt.Sprintf("Error creating temp directory for %s: %v", candidate.ToolName, err))
+			continue
+		}
+
+		reporter.Info(fmt.Sprintf("Downloading: %s", candidate.Asset.Name))
+		verifyOpts := ResolveVerifyOptions(a.Config, candidate.RepoURL, a.NoVerify)
+		digest, err := ExtractRelease(candidate.Asset, candidate.Release, tempDir, verifyOpts)
+		if err != nil {
+			reporter.Error(fmt.Sprintf("Error extracting release for %s: %v", candidate.ToolName, err))
+			RemoveDir(tempDir)
+			continue
+		}
+		reporter.Success(fmt.Sprintf("Downloaded: %s", candidate.Asset.Name))
+
+		if err := CheckTamper(&Release{TagName: candidate.CurrentVersion, VerifiedDigest: candidate.PreviousDigest}, candidate.NewVersion, digest); err != nil {
+			reporter.Error(err.Error())
+			RemoveDir(tempDir)
+			continue
+		}
+
+		reporter.Info(fmt.Sprintf("Extracting: %s", candidate.Asset.Name))
+
+		// A previously resolved multi-binary mapping pins the same basenames
+		// in the new archive instead of re-guessing with FindExecutable
+		spec := BinarySpec{}
+		if len(candidate.PreviousBinaries) > 1 {
+			names := make([]string, len(candidate.PreviousBinaries))
+			for i, name := range candidate.PreviousBinaries {
+				names[i] = name + ExecutableExt()
+			}
+			spec = BinarySpec{Names: names}
+		}
+		resolved, err := ResolveBinaries(tempDir, spec)
+		if err != nil {
+			reporter.Error(fmt.Sprintf("Error resolving binaries for %s: %v", candidate.ToolName, err))
+			RemoveDir(tempDir)
+			continue
+		}
+
+		var destPath string
+		var installedNames []string
+		installFailed := false
+		for _, bin := range resolved {
+			name := NormalizeBinaryName(bin.Name)
+			if len(resolved) == 1 {
+				name = candidate.ToolName
+			}
+			binDestPath := filepath.Join(a.Config.GetPath(), name+ExecutableExt())
+			if err := InstallBin(bin.SourcePath, binDestPath, false, name); err != nil {
+				reporter.Error(fmt.Sprintf("Error installing binary %s: %v", name, err))
+				installFailed = true
+				break
+			}
+			installedNames = append(installedNames, name)
+			destPath = binDestPath
+		}
+		if installFailed {
+			RemoveDir(tempDir)
+			continue
+		}
+
+		var auxiliaryPaths []string
+		if auxFiles, err := ScanAuxiliaryFiles(tempDir); err == nil && len(auxFiles) > 0 {
+			if installedAux, err := InstallAuxiliaryFiles(auxFiles); err != nil {
+				reporter.Error(fmt.Sprintf("error installing completions/man pages: %v", err))
+			} else {
+				auxiliaryPaths = installedAux
+			}
+		}
+		if shimPath := EnsureBinOnPath(reporter, a.Config.GetPath(), destPath, candidate.ToolName); shimPath != "" {
+			auxiliaryPaths = append(auxiliaryPaths, shimPath)
+		}
+
+		candidate.Release.Assets = []ReleaseAssets{*candidate.Asset}
+		candidate.Release.Binaries = installedNames
+		candidate.Release.AuxiliaryPaths = auxiliaryPaths
+		candidate.Release.VerifiedDigest = digest
+		candidate.Release.Uninstalled = false
+		candidate.Release.History = candidate.PreviousHistory
+		candidate.Release.Hooks = candidate.PreviousHooks
+		AppendHistory(candidate.Release, fmt.Sprintf("upgraded from %s to %s", candidate.CurrentVersion, candidate.NewVersion))
+		a.State.SetItem(candidate.Key, candidate.Release)
+
+		hookEnv["IR_ASSET_PATH"] = destPath
+		if err := RunHooks(ResolveHooks(a.Config, candidate.Release.Hooks), HookPostUpgrade, hookEnv); err != nil {
+			reporter.Error(err.Error())
+		}
+		NotifyEvent(a.Config, HookPostUpgrade, hookEnv)
+
+		reporter.Success(fmt.Sprintf("Installed: %s", candidate.ToolName))
+		RemoveDir(tempDir)
+	}
+}
+
+func (a *Upgrade) reporter() Reporter {
+	if a.Reporter != nil {
+		return a.Reporter
+	}
+	return StdoutReporter{}
+}
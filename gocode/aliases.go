@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// archAliases is the built-in OS/arch alias table used to expand a system's
+// canonical arch into the synonyms release maintainers commonly use in asset
+// names, e.g. x86_64 -> amd64, x64
+var archAliases = map[string][]string{
+	"x86_64":  {"x86", "x64", "amd64", "amd", "x86_64"},
+	"aarch64": {"arm64", "aarch64", "arm"},
+}
+
+// AliasesFile mirrors the structure of the user-supplied aliases.yaml
+type AliasesFile struct {
+	Arch map[string][]string `yaml:"arch"`
+}
+
+// aliasesOverridePath returns where a user can override or extend
+// archAliases, per-machine
+func aliasesOverridePath() string {
+	return filepath.Join(HOME, ".install-release", "aliases.yaml")
+}
+
+// loadArchAliases returns the built-in archAliases merged with any overrides
+// from aliasesOverridePath; an entry in the override file replaces the
+// built-in list for that arch rather than appending to it. Missing or
+// unreadable override files are silently ignored, falling back to the
+// built-in table.
+func loadArchAliases() map[string][]string {
+	merged := make(map[string][]string, len(archAliases))
+	for arch, aliases := range archAliases {
+		merged[arch] = aliases
+	}
+
+	data, err := os.ReadFile(aliasesOverridePath())
+	if err != nil {
+		return merged
+	}
+
+	var overrides AliasesFile
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return merged
+	}
+
+	for arch, aliases := range overrides.Arch {
+		merged[strings.ToLower(arch)] = aliases
+	}
+
+	return merged
+}
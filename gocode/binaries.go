@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BinarySpec declares which files inside an extracted archive are the
+// binaries to install, for packages that ship more than one (e.g. a
+// `kubectl` tarball bundling `kubectl-convert`, or a Go toolchain tarball
+// shipping `go` and `gofmt`). A zero-value BinarySpec falls back to the
+// existing FindExecutable heuristic.
+type BinarySpec struct {
+	// Names lists expected binary basenames to match exactly
+	Names []string
+	// Pattern is a glob (filepath.Match syntax) matched against each
+	// candidate's basename, e.g. "kube*"
+	Pattern string
+	// Under restricts the search to files whose path relative to the
+	// extracted directory matches this glob, e.g. "bin/*"
+	Under string
+	// Rename maps a matched basename to the name it should be installed
+	// under, e.g. {"kubectl-v1.2.3": "kubectl"}
+	Rename map[string]string
+}
+
+// IsEmpty reports whether spec has no constraints, i.e. ResolveBinaries
+// should fall back to the FindExecutable heuristic
+func (spec BinarySpec) IsEmpty() bool {
+	return len(spec.Names) == 0 && spec.Pattern == "" && spec.Under == "" && len(spec.Rename) == 0
+}
+
+// ResolvedBinary is one file resolved out of an extracted archive, paired
+// with the name it should be installed under
+type ResolvedBinary struct {
+	SourcePath string
+	Name       string
+}
+
+// ResolveBinaries finds the binaries to install out of extractedDir
+// according to spec. An empty spec falls back to the current
+// single-binary FindExecutable heuristic; a non-empty spec matches every
+// file satisfying Under/Names/Pattern, so multi-binary packages are
+// resolved deterministically instead of guessed at.
+func ResolveBinaries(extractedDir string, spec BinarySpec) ([]ResolvedBinary, error) {
+	if spec.IsEmpty() {
+		path, err := FindExecutable(extractedDir)
+		if err != nil {
+			return nil, err
+		}
+		return []ResolvedBinary{{SourcePath: path, Name: filepath.Base(path)}}, nil
+	}
+
+	var resolved []ResolvedBinary
+
+	err := filepath.Walk(extractedDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(extractedDir, path)
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+
+		if spec.Under != "" {
+			if ok, _ := filepath.Match(spec.Under, rel); !ok {
+				return nil
+			}
+		}
+
+		matched := len(spec.Names) == 0 && spec.Pattern == ""
+		for _, expected := range spec.Names {
+			if name == expected {
+				matched = true
+			}
+		}
+		if !matched && spec.Pattern != "" {
+			if ok, _ := filepath.Match(spec.Pattern, name); ok {
+				matched = true
+			}
+		}
+		if !matched {
+			return nil
+		}
+
+		installName := name
+		if renamed, ok := spec.Rename[name]; ok {
+			installName = renamed
+		}
+
+		resolved = append(resolved, ResolvedBinary{SourcePath: path, Name: installName})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error walking directory: %v", err)
+	}
+
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no binaries matched spec in %s", extractedDir)
+	}
+
+	return resolved, nil
+}
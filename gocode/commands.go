@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,23 +12,42 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
+// resolvedInstall is the outcome of resolving a single URL passed to `get`,
+// gathered concurrently before any confirmation prompt or install happens.
+type resolvedInstall struct {
+	url      string
+	toolName string
+	install  *Install
+	repoInfo *RepositoryInfo
+	releases []*Release
+	asset    *ReleaseAssets
+	err      error
+}
+
 // getCmd represents the get command
 func getCmd() *cobra.Command {
 	var tagName string
 	var name string
 	var approve bool
+	var noVerify bool
+	var pick string
+	var exclude []string
+	var jobs int
+	var continueOnError bool
+	var osName string
+	var arch string
+	var libc string
 
 	cmd := &cobra.Command{
-		Use:          "get [URL]",
+		Use:          "get [URL] [URL...]",
 		Short:        "Install GitHub/GitLab release, cli tool",
-		Long:         `Install a tool from GitHub or GitLab releases`,
-		Args:         cobra.ExactArgs(1),
+		Long:         `Install one or more tools from GitHub or GitLab releases`,
+		Args:         cobra.MinimumNArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			url := args[0]
-
 			// Load configuration
 			config := NewConfigManager()
 			if err := config.Load(); err != nil {
@@ -35,71 +55,99 @@ func getCmd() *cobra.Command {
 			}
 			// Debug print of config token removed for security
 
-			// Get repository info
-			repo, err := GetRepoInfo(url, config.GetToken(), config.GetGitlabToken())
-			if err != nil {
-				return fmt.Errorf("error getting repository info: %v", err)
-			}
-
-			// Get repository information
-			repoInfo, err := repo.Repository()
-			if err != nil {
-				return fmt.Errorf("error getting repository: %v", err)
+			// --name only makes sense for a single URL; with a batch, every
+			// tool derives its name from its own repository URL instead.
+			toolName := name
+			if len(args) > 1 {
+				toolName = ""
 			}
 
-			// Get releases
-			releases, err := repo.Release(tagName, config.GetPreRelease())
-			if err != nil {
-				return fmt.Errorf("error getting releases: %v", err)
-			}
+			state := NewStateManager()
 
-			if len(releases) == 0 {
-				return fmt.Errorf("no releases found")
-			}
+			// Resolve every URL concurrently (bounded by --jobs), mirroring
+			// the worker-pool pattern upgradeCmd uses to check for updates.
+			resolved := make([]resolvedInstall, len(args))
+			semaphore := make(chan struct{}, jobs)
+			var wg sync.WaitGroup
+			for i, url := range args {
+				wg.Add(1)
+				go func(i int, url string) {
+					defer wg.Done()
+					semaphore <- struct{}{}
+					defer func() { <-semaphore }()
 
-			// Find the best asset
-			asset, err := GetRelease(releases, url, nil)
-			if err != nil {
-				return fmt.Errorf("error finding release: %v", err)
+					install := &Install{
+						Config:   config,
+						State:    state,
+						URL:      url,
+						Name:     toolName,
+						TagName:  tagName,
+						NoVerify: noVerify,
+						Pick:     pick,
+						Exclude:  exclude,
+						OS:       osName,
+						Arch:     arch,
+						Libc:     libc,
+					}
+					repoInfo, releases, asset, err := install.Resolve()
+					resolved[i] = resolvedInstall{
+						url: url, install: install,
+						repoInfo: repoInfo, releases: releases, asset: asset, err: err,
+					}
+				}(i, url)
 			}
+			wg.Wait()
 
-			// Determine tool name - use repo name if no name provided
-			var toolName string
-			if name != "" {
-				toolName = name
-			} else {
-				// Extract repository name from URL
-				// URL format: https://github.com/owner/repo
-				parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
-				if len(parts) >= 2 {
-					toolName = parts[len(parts)-1] // Get the repo name (last part)
+			var toInstall []resolvedInstall
+			for _, r := range resolved {
+				if r.err != nil {
+					PrintError(fmt.Sprintf("%s: %v", r.url, r.err))
+					if !continueOnError {
+						return r.err
+					}
+					continue
+				}
+				if r.install.Name != "" {
+					r.toolName = r.install.Name
 				} else {
-					toolName = "unknown"
+					r.toolName = toolNameFromURL(r.url)
 				}
+				toInstall = append(toInstall, r)
+			}
+			if len(toInstall) == 0 {
+				return fmt.Errorf("no tool could be resolved")
 			}
 
-			// Show information
-			fmt.Printf("\n📑 Repo     : %s\n", repoInfo.FullName)
-			fmt.Printf("🌟 Stars    : %d\n", repoInfo.StargazersCount)
-			fmt.Printf("✨ Language : %s\n", repoInfo.Language)
-			fmt.Printf("🔥 Title    : %s\n", repoInfo.Description)
+			// Show repository information, but only for a single tool - a
+			// batch install goes straight to the asset table below.
+			if len(toInstall) == 1 {
+				repoInfo := toInstall[0].repoInfo
+				fmt.Printf("\n📑 Repo     : %s\n", repoInfo.FullName)
+				fmt.Printf("🌟 Stars    : %d\n", repoInfo.StargazersCount)
+				fmt.Printf("✨ Language : %s\n", repoInfo.Language)
+				fmt.Printf("🔥 Title    : %s\n", repoInfo.Description)
+			}
 
 			// Display installation title in bold green
-			installTitle := fmt.Sprintf("                              🚀 Install: %s", toolName)
+			installTitle := "                              🚀 Install"
+			if len(toInstall) == 1 {
+				installTitle += ": " + toInstall[0].toolName
+			}
 			installStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("10")).
 				Bold(true)
 			fmt.Printf("\n%s\n", installStyle.Render(installTitle))
 
-			// Prepare asset table data
-			assetRows := []map[string]string{
-				{
-					"Name":          toolName,
-					"Selected Item": asset.Name,
-					"Version":       releases[0].TagName,
-					"Size Mb":       fmt.Sprintf("%.1f", asset.SizeMB()),
-					"Downloads":     fmt.Sprintf("%d", asset.DownloadCount),
-				},
+			// Prepare asset table data, one row per tool
+			var assetRows []map[string]string
+			for _, r := range toInstall {
+				assetRows = append(assetRows, map[string]string{
+					"Name":          r.toolName,
+					"Selected Item": r.asset.Name,
+					"Version":       r.releases[0].TagName,
+					"Size Mb":       fmt.Sprintf("%.1f", r.asset.SizeMB()),
+					"Downloads":     fmt.Sprintf("%d", r.asset.DownloadCount),
+				})
 			}
 			assetHeaders := []string{"Name", "Selected Item", "Version", "Size Mb", "Downloads"}
 			assetColorFuncs := []func(string) string{
@@ -127,51 +175,35 @@ func getCmd() *cobra.Command {
 				}
 			}
 
-			// Create temporary directory for extraction
-			tempDir := filepath.Join(GetTempDir(), "install-release", "extract")
-			if err := Mkdir(tempDir); err != nil {
-				return fmt.Errorf("error creating temp directory: %v", err)
-			}
-			defer RemoveDir(tempDir)
-
-			// Extract the release
-			if err := ExtractRelease(asset, tempDir); err != nil {
-				return fmt.Errorf("error extracting release: %v", err)
-			}
-
-			// Find the executable
-			executable, err := FindExecutable(tempDir)
-			if err != nil {
-				return fmt.Errorf("error finding executable: %v", err)
-			}
-
-			// Install the executable
-			installPath := config.GetPath()
-			var destName string
-			if name != "" {
-				destName = name
-			} else {
-				destName = toolName // Use the extracted repo name instead of executable filename
+			var failed int
+			for _, r := range toInstall {
+				if _, err := r.install.Run(r.releases, r.asset); err != nil {
+					PrintError(fmt.Sprintf("%s: %v", r.toolName, err))
+					failed++
+					if !continueOnError {
+						return err
+					}
+				}
 			}
-
-			destPath := filepath.Join(installPath, destName)
-			if err := InstallBin(executable, destPath, false, destName); err != nil {
-				return fmt.Errorf("error installing binary: %v", err)
+			if failed > 0 {
+				return fmt.Errorf("%d tool(s) failed to install", failed)
 			}
 
-			// Save to state - matches Python pattern: cache[key] = release
-			// Only store the selected asset (like Python version)
-			releases[0].Assets = []ReleaseAssets{*asset}
-			state := NewStateManager()
-			state.SetByName(url, destName, releases[0])
-
 			return nil
 		},
 	}
 
 	cmd.Flags().StringVarP(&tagName, "tag", "t", "", "get a specific tag version")
-	cmd.Flags().StringVarP(&name, "name", "n", "", "tool name you want")
+	cmd.Flags().StringVarP(&name, "name", "n", "", "tool name you want (ignored when installing more than one URL)")
 	cmd.Flags().BoolVarP(&approve, "approve", "y", false, "Approve without Prompt")
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false, "skip checksum and signature verification")
+	cmd.Flags().StringVar(&pick, "pick", "", "glob pattern that, if set, selects the asset directly, e.g. '*-linux-musl-amd64.tar.gz'")
+	cmd.Flags().StringSliceVar(&exclude, "exclude", nil, "regex pattern to exclude matching assets, can be repeated, e.g. --exclude debug")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", 5, "number of URLs to resolve concurrently")
+	cmd.Flags().BoolVar(&continueOnError, "continue-on-error", false, "keep going if one URL fails to resolve or install")
+	cmd.Flags().StringVar(&osName, "os", "", "target OS to select an asset for, overriding the host OS, e.g. linux")
+	cmd.Flags().StringVar(&arch, "arch", "", "target architecture to select an asset for, overriding the host architecture, e.g. arm64")
+	cmd.Flags().StringVar(&libc, "libc", "", "libc flavor to match, \"musl\" or \"glibc\", for projects that publish both")
 
 	return cmd
 }
@@ -180,6 +212,10 @@ func getCmd() *cobra.Command {
 func upgradeCmd() *cobra.Command {
 	var force bool
 	var skipPrompt bool
+	var noVerify bool
+	var osName string
+	var arch string
+	var libc string
 
 	cmd := &cobra.Command{
 		Use:          "upgrade",
@@ -199,191 +235,65 @@ func upgradeCmd() *cobra.Command {
 				return fmt.Errorf("error loading config: %v", err)
 			}
 
-			items := state.Items()
-			if len(items) == 0 {
+			if len(state.Items()) == 0 {
 				PrintInfo("No installed tools found")
 				return nil
 			}
 
-			// Track upgrades available
-			type UpgradeInfo struct {
-				name           string
-				currentVersion string
-				newVersion     string
-				release        *Release
-				asset          *ReleaseAssets
-				repoURL        string
-				key            string
+			upgrade := &Upgrade{
+				Config:   config,
+				State:    state,
+				Force:    force,
+				NoVerify: noVerify,
+				OS:       osName,
+				Arch:     arch,
+				Libc:     libc,
 			}
+			candidates := upgrade.Plan()
 
-			var availableUpgrades []UpgradeInfo
-
-			// Check all tools for updates with concurrency control (max 5 concurrent)
-			const maxConcurrent = 5
-			semaphore := make(chan struct{}, maxConcurrent)
-			var wg sync.WaitGroup
-			var mu sync.Mutex
-
-			for key, release := range items {
-				// Extract tool name from key
-				var toolName string
-				if strings.Contains(key, "#") {
-					parts := strings.Split(key, "#")
-					toolName = parts[len(parts)-1]
-				} else {
-					toolName = key
-				}
-
-				if release.HoldUpdate {
-					continue
-				}
-
-				wg.Add(1)
-				go func(key string, release *Release, toolName string) {
-					defer wg.Done()
-
-					// Acquire semaphore
-					semaphore <- struct{}{}
-					defer func() { <-semaphore }()
-
-					fmt.Printf("Fetching: %s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Render(key))
-
-					// Get repository info
-					repo, err := GetRepoInfo(release.URL, config.GetToken(), config.GetGitlabToken())
-					if err != nil {
-						return
-					}
-
-					// Get latest release
-					releases, err := repo.Release("", config.GetPreRelease())
-					if err != nil {
-						return
-					}
-
-					if len(releases) == 0 {
-						return
-					}
-
-					latestRelease := releases[0]
-
-					// Check if update is available (compare versions or force)
-					if latestRelease.TagName != release.TagName || force {
-						// Find the best asset
-						asset, err := GetRelease(releases, release.URL, nil)
-						if err != nil {
-							return
-						}
-
-						// Thread-safe append to availableUpgrades
-						mu.Lock()
-						availableUpgrades = append(availableUpgrades, UpgradeInfo{
-							name:           toolName,
-							currentVersion: release.TagName,
-							newVersion:     latestRelease.TagName,
-							release:        latestRelease,
-							asset:          asset,
-							repoURL:        release.URL,
-							key:            key,
-						})
-						mu.Unlock()
-					}
-				}(key, release, toolName)
+			// Show available upgrades and ask for confirmation (like Python version)
+			if len(candidates) == 0 {
+				fmt.Printf("All tools are onto latest version\n")
+				return nil
 			}
 
-			// Wait for all goroutines to complete
-			wg.Wait()
-
-			// Show available upgrades and ask for confirmation (like Python version)
-			if len(availableUpgrades) > 0 {
-				fmt.Printf("\nFollowing tool will get upgraded.\n\n")
+			fmt.Printf("\nFollowing tool will get upgraded.\n\n")
 
-				// Show tool names in one line like Python version
-				toolNames := make([]string, len(availableUpgrades))
-				for i, upgrade := range availableUpgrades {
-					toolNames[i] = upgrade.name
-				}
-				toolNamesStr := strings.Join(toolNames, " ")
-				fmt.Printf("%s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true).Render(toolNamesStr))
-
-				if !skipPrompt {
-					prompt := lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Bold(true).Render("Upgrade these tools, (Y/n): ")
-					fmt.Printf("\n%s", prompt)
-					reader := bufio.NewReader(os.Stdin)
-					response, err := reader.ReadString('\n')
-					if err != nil {
-						return fmt.Errorf("error reading input: %v", err)
-					}
+			// Show tool names in one line like Python version
+			toolNames := make([]string, len(candidates))
+			for i, candidate := range candidates {
+				toolNames[i] = candidate.ToolName
+			}
+			toolNamesStr := strings.Join(toolNames, " ")
+			fmt.Printf("%s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true).Render(toolNamesStr))
 
-					response = strings.TrimSpace(strings.ToLower(response))
-					if response != "" && response != "y" && response != "yes" {
-						fmt.Println("Upgrade cancelled")
-						return nil
-					}
+			if !skipPrompt {
+				prompt := lipgloss.NewStyle().Foreground(lipgloss.Color("117")).Bold(true).Render("Upgrade these tools, (Y/n): ")
+				fmt.Printf("\n%s", prompt)
+				reader := bufio.NewReader(os.Stdin)
+				response, err := reader.ReadString('\n')
+				if err != nil {
+					return fmt.Errorf("error reading input: %v", err)
 				}
 
-				// Perform upgrades
-				for _, upgrade := range availableUpgrades {
-					fmt.Printf("Updating: %s, %s => %s\n", upgrade.name, upgrade.currentVersion, upgrade.newVersion)
-
-					// Show download progress
-					downloadMsg := fmt.Sprintf("ℹ️  Downloading: %s", upgrade.asset.Name)
-					fmt.Printf("%s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Render(downloadMsg))
-
-					// Create temporary directory for extraction
-					tempDir := filepath.Join(GetTempDir(), "install-release", "extract", upgrade.name)
-					if err := Mkdir(tempDir); err != nil {
-						fmt.Printf("Error creating temp directory for %s: %v\n", upgrade.name, err)
-						continue
-					}
-					defer RemoveDir(tempDir)
-
-					// Extract the release
-					if err := ExtractRelease(upgrade.asset, tempDir); err != nil {
-						fmt.Printf("Error extracting release for %s: %v\n", upgrade.name, err)
-						continue
-					}
-
-					// Show download complete
-					downloadedMsg := fmt.Sprintf("✅ Downloaded: %s", upgrade.asset.Name)
-					fmt.Printf("%s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(downloadedMsg))
-
-					// Show extraction progress
-					extractMsg := fmt.Sprintf("ℹ️  Extracting: %s", upgrade.asset.Name)
-					fmt.Printf("%s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Render(extractMsg))
-
-					// Find the executable
-					executable, err := FindExecutable(tempDir)
-					if err != nil {
-						fmt.Printf("Error finding executable for %s: %v\n", upgrade.name, err)
-						continue
-					}
-
-					// Install the executable
-					installPath := config.GetPath()
-					destPath := filepath.Join(installPath, upgrade.name)
-					if err := InstallBin(executable, destPath, false, upgrade.name); err != nil {
-						fmt.Printf("Error installing binary for %s: %v\n", upgrade.name, err)
-						continue
-					}
-
-					// Update state - only store the selected asset (like Python version)
-					upgrade.release.Assets = []ReleaseAssets{*upgrade.asset}
-					state.SetItem(upgrade.key, upgrade.release)
-
-					// Show success message in bold blue
-					successMsg := fmt.Sprintf("Installed: %s", upgrade.name)
-					fmt.Printf("%s\n", lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Bold(true).Render(successMsg))
+				response = strings.TrimSpace(strings.ToLower(response))
+				if response != "" && response != "y" && response != "yes" {
+					fmt.Println("Upgrade cancelled")
+					return nil
 				}
-			} else {
-				fmt.Printf("All tools are onto latest version\n")
 			}
 
+			upgrade.Apply(candidates)
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVarP(&force, "force", "F", false, "set force")
 	cmd.Flags().BoolVarP(&skipPrompt, "skip-prompt", "y", false, "skip confirmation (y/n) prompt")
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false, "skip checksum and signature verification")
+	cmd.Flags().StringVar(&osName, "os", "", "target OS to select an asset for, overriding the host OS, e.g. linux")
+	cmd.Flags().StringVar(&arch, "arch", "", "target architecture to select an asset for, overriding the host architecture, e.g. arm64")
+	cmd.Flags().StringVar(&libc, "libc", "", "libc flavor to match, \"musl\" or \"glibc\", for projects that publish both")
 
 	return cmd
 }
@@ -416,6 +326,9 @@ func listCmd() *cobra.Command {
 			// Prepare data for PrintTable
 			var tableRows []map[string]string
 			for name, release := range items {
+				if release.Uninstalled {
+					continue
+				}
 				// Show all tools when not using --hold flag, or only held tools when using --hold flag
 				if !hold || release.HoldUpdate {
 					toolName := name
@@ -462,6 +375,8 @@ func listCmd() *cobra.Command {
 
 // removeCmd represents the remove command
 func removeCmd() *cobra.Command {
+	var purge bool
+
 	cmd := &cobra.Command{
 		Use:          "rm [NAME]",
 		Short:        "Remove any installed releases, cli tools",
@@ -483,29 +398,13 @@ func removeCmd() *cobra.Command {
 				return fmt.Errorf("error loading config: %v", err)
 			}
 
-			// Try to find by tool name using the new methods
-			_, key, found := state.GetByName(name)
-			if !found {
-				return fmt.Errorf("tool %s not found", name)
-			}
-
-			// Remove the executable
-			installPath := config.GetPath()
-			executablePath := filepath.Join(installPath, name)
-			if Exists(executablePath) {
-				if err := RemoveFile(executablePath); err != nil {
-					return fmt.Errorf("error removing executable: %v", err)
-				}
-			}
-
-			// Remove from state
-			state.DelItem(key)
-
-			PrintSuccess("Removed: " + name)
-			return nil
+			remove := &Remove{Config: config, State: state, Name: name, Purge: purge}
+			return remove.Run()
 		},
 	}
 
+	cmd.Flags().BoolVar(&purge, "purge", false, "also erase the tool's install history instead of keeping it for 'ir history'/'ir rollback'")
+
 	return cmd
 }
 
@@ -515,6 +414,23 @@ func configCmd() *cobra.Command {
 	var gitlabToken string
 	var path string
 	var preRelease bool
+	var minisignPubKey string
+	var cosignPubKey string
+	var requireSignature bool
+	var githubEnterpriseHost string
+	var gitlabHost string
+	var giteaHost string
+	var urlTemplate string
+	var hostToken string
+	var mustMatch string
+	var mustNotMatch string
+	var preferExtension string
+	var trustedKey string
+	var assetOverride string
+	var stateBackend string
+	var hook string
+	var notifyWebhook string
+	var notifySlack string
 
 	cmd := &cobra.Command{
 		Use:          "config",
@@ -552,6 +468,123 @@ func configCmd() *cobra.Command {
 				}
 			}
 
+			if minisignPubKey != "" {
+				config.SetMinisignPubKey(minisignPubKey)
+				fmt.Println("Updated minisign public key")
+			}
+
+			if cosignPubKey != "" {
+				config.SetCosignPubKey(cosignPubKey)
+				fmt.Println("Updated cosign public key")
+			}
+
+			if cmd.Flags().Changed("require-signature") {
+				config.SetRequireSignature(requireSignature)
+				if requireSignature {
+					fmt.Println("Release signatures are now required")
+				} else {
+					fmt.Println("Release signatures are no longer required")
+				}
+			}
+
+			if githubEnterpriseHost != "" {
+				config.AddGithubEnterpriseHost(githubEnterpriseHost)
+				fmt.Printf("Whitelisted GitHub Enterprise host: %s\n", githubEnterpriseHost)
+			}
+
+			if gitlabHost != "" {
+				config.AddGitlabHost(gitlabHost)
+				fmt.Printf("Whitelisted self-hosted GitLab host: %s\n", gitlabHost)
+			}
+
+			if giteaHost != "" {
+				config.AddGiteaHost(giteaHost)
+				fmt.Printf("Whitelisted self-hosted Gitea/Forgejo host: %s\n", giteaHost)
+			}
+
+			if urlTemplate != "" {
+				repoURL, tmpl, ok := strings.Cut(urlTemplate, "=")
+				if !ok || repoURL == "" || tmpl == "" {
+					return fmt.Errorf("--url-template must be in the form repo-url=template")
+				}
+				config.SetURLTemplate(repoURL, tmpl)
+				fmt.Printf("Pinned URL template for: %s\n", repoURL)
+			}
+
+			if hostToken != "" {
+				host, hostTok, ok := strings.Cut(hostToken, "=")
+				if !ok || host == "" || hostTok == "" {
+					return fmt.Errorf("--host-token must be in the form host=token")
+				}
+				config.SetHostToken(host, hostTok)
+				fmt.Printf("Updated token for host: %s\n", host)
+			}
+
+			if mustMatch != "" {
+				config.AddMustMatch(mustMatch)
+				fmt.Printf("Added default must-match pattern: %s\n", mustMatch)
+			}
+
+			if mustNotMatch != "" {
+				config.AddMustNotMatch(mustNotMatch)
+				fmt.Printf("Added default must-not-match pattern: %s\n", mustNotMatch)
+			}
+
+			if preferExtension != "" {
+				config.AddPreferExtensions(preferExtension)
+				fmt.Printf("Added default preferred extension: %s\n", preferExtension)
+			}
+
+			if trustedKey != "" {
+				repoURL, pubKey, ok := strings.Cut(trustedKey, "=")
+				if !ok || repoURL == "" || pubKey == "" {
+					return fmt.Errorf("--trusted-key must be in the form repo-url=pubkey")
+				}
+				config.SetTrustedKey(repoURL, pubKey)
+				fmt.Printf("Pinned trusted key for: %s\n", repoURL)
+			}
+
+			if assetOverride != "" {
+				repoURL, fields, ok := strings.Cut(assetOverride, "=")
+				if !ok || repoURL == "" || fields == "" {
+					return fmt.Errorf("--asset-override must be in the form repo-url=os:..,arch:..,libc:..,pattern:..")
+				}
+				override, err := parseRepoOverride(fields)
+				if err != nil {
+					return fmt.Errorf("--asset-override: %v", err)
+				}
+				config.SetAssetOverride(repoURL, override)
+				fmt.Printf("Pinned asset override for: %s\n", repoURL)
+			}
+
+			if stateBackend != "" {
+				if err := config.SetStateBackend(stateBackend); err != nil {
+					return err
+				}
+				fmt.Printf("Set state backend to: %s\n", stateBackend)
+			}
+
+			if hook != "" {
+				event, command, ok := strings.Cut(hook, "=")
+				if !ok || event == "" || command == "" {
+					return fmt.Errorf("--hook must be in the form event=command")
+				}
+				if err := config.AddHook(event, command); err != nil {
+					return err
+				}
+				fmt.Printf("Added %s hook: %s\n", event, command)
+			}
+
+			if notifyWebhook != "" {
+				config.SetNotifyWebhook(notifyWebhook)
+				fmt.Println("Updated notification webhook URL")
+			}
+
+			if notifySlack != "" {
+				config.SetNotifySlack(notifySlack)
+				fmt.Println("Updated Slack notification webhook URL")
+			}
+
 			return nil
 		},
 	}
@@ -560,10 +593,78 @@ func configCmd() *cobra.Command {
 	cmd.Flags().StringVar(&gitlabToken, "gitlab-token", "", "set your GitLab token to solve API rate-limiting issue")
 	cmd.Flags().StringVar(&path, "path", "", "set install path")
 	cmd.Flags().BoolVar(&preRelease, "pre-release", false, "Also include pre-releases while checking updates")
+	cmd.Flags().StringVar(&githubEnterpriseHost, "github-enterprise-host", "", "whitelist a GitHub Enterprise host (e.g. ghe.mycorp.com)")
+	cmd.Flags().StringVar(&gitlabHost, "gitlab-host", "", "whitelist a self-hosted GitLab host (e.g. gitlab.mycorp.com)")
+	cmd.Flags().StringVar(&giteaHost, "gitea-host", "", "whitelist a self-hosted Gitea/Forgejo host (e.g. git.mycorp.com); codeberg.org is trusted by default")
+	cmd.Flags().StringVar(&urlTemplate, "url-template", "", "pin a direct-download URL template for a repo with no API support, in the form repo-url=template (template has .Version/.OS/.Arch, e.g. https://example.com/{{.Version}}/tool_{{.OS}}_{{.Arch}}.tar.gz)")
+	cmd.Flags().StringVar(&hostToken, "host-token", "", "set a token for a specific host, in the form host=token")
+	cmd.Flags().StringVar(&minisignPubKey, "minisign-pub-key", "", "set the trusted minisign public key used to verify release signatures")
+	cmd.Flags().StringVar(&cosignPubKey, "cosign-pub-key", "", "set the trusted cosign public key used to verify release signatures")
+	cmd.Flags().BoolVar(&requireSignature, "require-signature", false, "fail installation if a release has no signature")
+	cmd.Flags().StringVar(&trustedKey, "trusted-key", "", "pin a minisign/cosign public key for a specific repo, in the form repo-url=pubkey")
+	cmd.Flags().StringVar(&mustMatch, "must-match", "", "add a default asset-selector must-match pattern, applied to every `get`/`upgrade` unless overridden by --pick")
+	cmd.Flags().StringVar(&mustNotMatch, "must-not-match", "", "add a default asset-selector must-not-match pattern")
+	cmd.Flags().StringVar(&preferExtension, "prefer-extension", "", "add a default asset-selector preferred extension, in priority order")
+	cmd.Flags().StringVar(&assetOverride, "asset-override", "", "pin an asset-selection override for a repo, in the form repo-url=os:linux,arch:arm64,libc:musl,pattern:*.tar.gz (fields optional)")
+	cmd.Flags().StringVar(&stateBackend, "state-backend", "", "storage backend for installed tool state: file, dir or sqlite")
+	cmd.Flags().StringVar(&hook, "hook", "", "add a global lifecycle hook command, in the form event=command (event is one of pre_install, post_install, pre_upgrade, post_upgrade, pre_remove, post_remove)")
+	cmd.Flags().StringVar(&notifyWebhook, "notify-webhook", "", "set the webhook URL POSTed a JSON payload on every hook-bearing lifecycle event")
+	cmd.Flags().StringVar(&notifySlack, "notify-slack", "", "set the Slack incoming-webhook URL notified the same way")
+
+	cmd.AddCommand(configMigrateCmd())
+
+	return cmd
+}
 
+// configMigrateCmd represents the config migrate command
+func configMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "migrate",
+		Short:        "Migrate the config file to the current schema version",
+		Long:         `Upgrade an on-disk config file predating schema versioning (or any older schema version) to the current one, backing up the original file first`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := NewConfigManager()
+			migrated, err := config.Migrate()
+			if err != nil {
+				return fmt.Errorf("error migrating config: %v", err)
+			}
+			if !migrated {
+				fmt.Printf("Config already at schema version %d\n", CurrentConfigSchemaVersion)
+				return nil
+			}
+			PrintSuccess(fmt.Sprintf("Migrated config to schema version %d", CurrentConfigSchemaVersion))
+			return nil
+		},
+	}
 	return cmd
 }
 
+// parseRepoOverride parses the comma-separated key:value fields of
+// --asset-override (os, arch, libc, pattern) into a RepoOverride
+func parseRepoOverride(fields string) (RepoOverride, error) {
+	var override RepoOverride
+	for _, field := range strings.Split(fields, ",") {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || key == "" || value == "" {
+			return RepoOverride{}, fmt.Errorf("invalid field %q, expected key:value", field)
+		}
+		switch key {
+		case "os":
+			override.OS = value
+		case "arch":
+			override.Arch = value
+		case "libc":
+			override.Libc = value
+		case "pattern":
+			override.Pattern = value
+		default:
+			return RepoOverride{}, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	return override, nil
+}
+
 // stateCmd represents the state command
 func stateCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -593,6 +694,32 @@ func stateCmd() *cobra.Command {
 		},
 	}
 
+	cmd.AddCommand(stateMigrateCmd())
+
+	return cmd
+}
+
+// stateMigrateCmd represents the state migrate command
+func stateMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "migrate",
+		Short:        "Migrate the state file to the current schema version",
+		Long:         `Upgrade an on-disk state file predating schema versioning (or any older schema version) to the current one, backing up the original file first. No-op for the dir/sqlite backends, which have no legacy format.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state := NewStateManager()
+			migrated, err := state.Migrate()
+			if err != nil {
+				return fmt.Errorf("error migrating state: %v", err)
+			}
+			if !migrated {
+				fmt.Printf("State already at schema version %d\n", CurrentStateSchemaVersion)
+				return nil
+			}
+			PrintSuccess(fmt.Sprintf("Migrated state to schema version %d", CurrentStateSchemaVersion))
+			return nil
+		},
+	}
 	return cmd
 }
 
@@ -604,16 +731,36 @@ func pullCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "pull",
 		Short:        "Install tools from a remote state",
-		Long:         `Install tools from a remote state file`,
+		Long:         `Install or upgrade a set of tools from a declarative manifest (local file or http(s) URL)`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if url == "" {
 				return fmt.Errorf("URL is required")
 			}
 
-			fmt.Printf("Pulling state from: %s\n", url)
-			fmt.Println("This feature is not yet implemented in the Go version")
+			fmt.Printf("Pulling manifest from: %s\n", url)
+			manifest, err := FetchManifest(url)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+			state := NewStateManager()
+
+			var failed int
+			for _, tool := range manifest.Tools {
+				if err := applyManifestTool(tool, config, state, override); err != nil {
+					PrintError(err.Error())
+					failed++
+				}
+			}
 
+			if failed > 0 {
+				return fmt.Errorf("%d tool(s) failed to install", failed)
+			}
 			return nil
 		},
 	}
@@ -624,80 +771,785 @@ func pullCmd() *cobra.Command {
 	return cmd
 }
 
-// holdCmd represents the hold command
-func holdCmd() *cobra.Command {
-	var unset bool
+// pushCmd represents the push command
+func pushCmd() *cobra.Command {
+	var output string
 
 	cmd := &cobra.Command{
-		Use:          "hold [NAME]",
-		Short:        "Keep updates a tool on hold",
-		Long:         `Hold or unhold updates for a specific tool`,
-		Args:         cobra.ExactArgs(1),
+		Use:          "push",
+		Short:        "Export the current state as a tools manifest",
+		Long:         `Export the currently installed tools as a declarative manifest consumable by 'ir pull'`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			name := args[0]
-
-			// Load state
 			state := NewStateManager()
 			if err := state.Load(); err != nil {
 				return fmt.Errorf("error loading state: %v", err)
 			}
 
-			// Check if tool exists using GetByName
-			release, _, exists := state.GetByName(name)
-			if !exists {
-				return fmt.Errorf("tool %s not found", name)
-			}
+			manifest := ExportManifest(state.Items())
 
-			if unset {
-				release.HoldUpdate = false
-				fmt.Printf("Unheld updates for %s\n", name)
+			var data []byte
+			var err error
+			if strings.HasSuffix(strings.ToLower(output), ".json") {
+				data, err = json.MarshalIndent(manifest, "", "  ")
 			} else {
-				release.HoldUpdate = true
-				fmt.Printf("Held updates for %s\n", name)
+				data, err = yaml.Marshal(manifest)
+			}
+			if err != nil {
+				return fmt.Errorf("error encoding manifest: %v", err)
 			}
 
-			// Update the release in state using the existing key
-			_, key, _ := state.GetByName(name)
-			state.SetItem(key, release)
+			if output == "" {
+				fmt.Print(string(data))
+				return nil
+			}
 
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("error writing manifest: %v", err)
+			}
+			PrintSuccess("Wrote manifest: " + output)
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&unset, "unset", true, "unset from hold")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write the manifest to this file instead of stdout (.json or .yaml)")
 
 	return cmd
 }
 
-// meCmd represents the me command
-func meCmd() *cobra.Command {
-	var update bool
-	var version bool
+// syncCmd represents the sync command
+func syncCmd() *cobra.Command {
+	var file string
+	var dryRun bool
+	var prune bool
 
 	cmd := &cobra.Command{
-		Use:          "me",
-		Short:        "Update ir tool",
-		Long:         `Update the install-release tool itself`,
+		Use:          "sync",
+		Short:        "Converge installed tools to match a toolsfile.yaml",
+		Long:         `Install missing tools, upgrade out-of-date ones, and (with --prune) remove tools present in state but absent from a declarative toolsfile (local file or http(s) URL, defaulting to ./toolsfile.yaml)`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if version {
-				fmt.Println("install-release v0.5.2 (Go version)")
-				return nil
+			manifest, err := FetchManifest(file)
+			if err != nil {
+				return err
 			}
 
-			if update {
-				fmt.Println("Updating install-release...")
-				fmt.Println("This feature is not yet implemented in the Go version")
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+			state := NewStateManager()
+
+			plan := PlanSync(manifest, state, prune)
+			if dryRun {
+				printSyncPlan(plan)
 				return nil
 			}
 
-			return cmd.Help()
+			failed := ApplySync(plan, config, state)
+			if failed > 0 {
+				return fmt.Errorf("%d action(s) failed", failed)
+			}
+			return nil
 		},
 	}
 
-	cmd.Flags().BoolVarP(&update, "upgrade", "U", false, "Update tool, install-release")
-	cmd.Flags().BoolVar(&version, "version", false, "print version this tool, install-release")
+	cmd.Flags().StringVarP(&file, "file", "f", "toolsfile.yaml", "toolsfile to sync from (local path or http(s) URL)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the planned actions without applying them")
+	cmd.Flags().BoolVar(&prune, "prune", false, "remove installed tools that are absent from the toolsfile")
+
+	cmd.AddCommand(syncExportCmd())
+
+	return cmd
+}
+
+// printSyncPlan renders a sync plan as a table, skipping nothing so a
+// --dry-run also confirms which tools are already up to date
+func printSyncPlan(plan []SyncAction) {
+	if len(plan) == 0 {
+		PrintInfo("Nothing to sync")
+		return
+	}
+
+	colorForAction := func(action string) string {
+		switch action {
+		case "install":
+			return InfoStyle.Render(action)
+		case "upgrade":
+			return SuccessStyle.Render(action)
+		case "remove":
+			return ErrorStyle.Render(action)
+		default:
+			return action
+		}
+	}
+
+	var rows []map[string]string
+	for _, a := range plan {
+		rows = append(rows, map[string]string{
+			"Name":    a.Name,
+			"Action":  colorForAction(a.Action),
+			"Current": a.Current,
+			"Desired": a.Desired,
+		})
+	}
+
+	PrintTable(rows, []string{"Name", "Action", "Current", "Desired"}, nil)
+}
+
+// syncExportCmd represents the sync export command
+func syncExportCmd() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:          "export",
+		Short:        "Generate a toolsfile.yaml from the current state",
+		Long:         `Export the currently installed tools as a toolsfile consumable by 'ir sync', identical to 'ir push' but named to match sync's vocabulary`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state := NewStateManager()
+			if err := state.Load(); err != nil {
+				return fmt.Errorf("error loading state: %v", err)
+			}
+
+			manifest := ExportManifest(state.Items())
+
+			var data []byte
+			var err error
+			if strings.HasSuffix(strings.ToLower(output), ".json") {
+				data, err = json.MarshalIndent(manifest, "", "  ")
+			} else {
+				data, err = yaml.Marshal(manifest)
+			}
+			if err != nil {
+				return fmt.Errorf("error encoding manifest: %v", err)
+			}
+
+			if output == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("error writing toolsfile: %v", err)
+			}
+			PrintSuccess("Wrote toolsfile: " + output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write the toolsfile to this path instead of stdout (.json or .yaml)")
+
+	return cmd
+}
+
+// holdCmd represents the hold command
+func holdCmd() *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:          "hold [NAME]",
+		Short:        "Keep updates a tool on hold",
+		Long:         `Hold or unhold updates for a specific tool`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			// Load state
+			state := NewStateManager()
+			if err := state.Load(); err != nil {
+				return fmt.Errorf("error loading state: %v", err)
+			}
+
+			hold := &Hold{State: state, Name: name, Unset: unset}
+			return hold.Run()
+		},
+	}
+
+	cmd.Flags().BoolVar(&unset, "unset", true, "unset from hold")
+
+	return cmd
+}
+
+// hooksCmd represents the hooks command
+func hooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "hooks",
+		Short:        "Inspect and test lifecycle hooks",
+		Long:         `View and test the shell hooks run around install/upgrade/remove`,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(hooksTestCmd())
+
+	return cmd
+}
+
+// hooksTestCmd represents the hooks test command
+func hooksTestCmd() *cobra.Command {
+	var event string
+
+	cmd := &cobra.Command{
+		Use:          "test NAME",
+		Short:        "Run a tool's hooks for a given event",
+		Long:         `Run the resolved hooks (per-tool override, falling back to the global config) for a tool and lifecycle event, without installing/upgrading/removing anything`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			if !IsValidHookEvent(event) {
+				return fmt.Errorf("unknown hook event %q, expected one of: pre_install, post_install, pre_upgrade, post_upgrade, pre_remove, post_remove", event)
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			state := NewStateManager()
+			if err := state.Load(); err != nil {
+				return fmt.Errorf("error loading state: %v", err)
+			}
+
+			release, _, found := state.GetByName(name)
+			if !found {
+				return fmt.Errorf("tool %s not found", name)
+			}
+
+			hooks := ResolveHooks(config, release.Hooks)
+			env := HookEnv(name, release.TagName, release.TagName, filepath.Join(config.GetPath(), name))
+			if err := RunHooks(hooks, HookEvent(event), env); err != nil {
+				return err
+			}
+			PrintSuccess(fmt.Sprintf("Ran %s hooks for %s", event, name))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&event, "event", "post_install", "lifecycle event to test: pre_install, post_install, pre_upgrade, post_upgrade, pre_remove, post_remove")
+
+	return cmd
+}
+
+// historyCmd represents the history command
+func historyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "history [NAME]",
+		Short:        "Show the install history of a tool",
+		Long:         `Show the recorded install/upgrade/rollback history for a tool`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			state := NewStateManager()
+			if err := state.Load(); err != nil {
+				return fmt.Errorf("error loading state: %v", err)
+			}
+
+			release, _, found := state.GetByName(name)
+			if !found {
+				return fmt.Errorf("tool %s not found", name)
+			}
+			if len(release.History) == 0 {
+				PrintInfo(fmt.Sprintf("No history recorded for %s", name))
+				return nil
+			}
+
+			PrintSection(fmt.Sprintf("History: %s", name))
+
+			var tableRows []map[string]string
+			for _, rev := range release.History {
+				tableRows = append(tableRows, map[string]string{
+					"Revision":     fmt.Sprintf("%d", rev.Revision),
+					"Version":      rev.TagName,
+					"Installed At": rev.InstalledAt,
+					"Description":  rev.Description,
+				})
+			}
+
+			headers := []string{"Revision", "Version", "Installed At", "Description"}
+			colorFuncs := []func(string) string{
+				func(s string) string { return lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render(s) }, // Light Yellow
+				func(s string) string { return lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Render(s) }, // Cyan
+				func(s string) string { return lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Render(s) }, // Light Blue
+				func(s string) string { return lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render(s) }, // Light Green
+			}
+			PrintTable(tableRows, headers, colorFuncs)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// rollbackCmd represents the rollback command
+func rollbackCmd() *cobra.Command {
+	var toTag string
+	var noVerify bool
+
+	cmd := &cobra.Command{
+		Use:          "rollback [NAME]",
+		Short:        "Roll back a tool to a previously installed version",
+		Long:         `Reinstall a tool at a prior tag recorded in its history`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			state := NewStateManager()
+			if err := state.Load(); err != nil {
+				return fmt.Errorf("error loading state: %v", err)
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			rollback := &Rollback{Config: config, State: state, Name: name, ToTag: toTag, NoVerify: noVerify}
+			_, err := rollback.Run()
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&toTag, "to", "", "roll back to this specific tag instead of the most recent prior version")
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false, "skip checksum and signature verification")
+
+	return cmd
+}
+
+// meCmd represents the me command
+func meCmd() *cobra.Command {
+	var update bool
+	var version bool
+
+	cmd := &cobra.Command{
+		Use:          "me",
+		Short:        "Update ir tool",
+		Long:         `Update the install-release tool itself`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if version {
+				fmt.Printf("install-release %s (Go version)\n", Version)
+				return nil
+			}
+
+			if update {
+				config := NewConfigManager()
+				if err := config.Load(); err != nil {
+					return fmt.Errorf("error loading config: %v", err)
+				}
+
+				PrintInfo("Checking for a newer install-release release...")
+				updated, newVersion, err := SelfUpdate(cmd.Context(), SelfUpdateOptions{
+					Token:            config.GetToken(),
+					PreRelease:       config.GetPreRelease(),
+					MinisignPubKey:   config.GetMinisignPubKey(),
+					CosignPubKey:     config.GetCosignPubKey(),
+					RequireSignature: config.GetRequireSignature(),
+				})
+				if err != nil {
+					return fmt.Errorf("error updating install-release: %v", err)
+				}
+
+				if !updated {
+					fmt.Printf("Already on the latest version: %s\n", Version)
+					return nil
+				}
+
+				PrintSuccess(fmt.Sprintf("Updated install-release: %s => %s", Version, newVersion))
+				return nil
+			}
+
+			return cmd.Help()
+		},
+	}
+
+	cmd.Flags().BoolVarP(&update, "upgrade", "U", false, "Update tool, install-release")
+	cmd.Flags().BoolVar(&version, "version", false, "print version this tool, install-release")
+
+	return cmd
+}
+
+// releaseCmd represents the release command, a group of subcommands for
+// publishing and managing GitHub/GitLab releases from the current repo -
+// the write-side counterpart to `get`/`upgrade`
+func releaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "release",
+		Short: "Publish and manage GitHub/GitLab releases",
+		Long:  `Create, list, show, edit, delete, and download releases of the current repository`,
+	}
+
+	cmd.AddCommand(releaseCreateCmd())
+	cmd.AddCommand(releaseListCmd())
+	cmd.AddCommand(releaseShowCmd())
+	cmd.AddCommand(releaseEditCmd())
+	cmd.AddCommand(releaseDeleteCmd())
+	cmd.AddCommand(releaseDownloadCmd())
+
+	return cmd
+}
+
+// releaseCreateCmd represents the release create command
+func releaseCreateCmd() *cobra.Command {
+	var repo string
+	var target string
+	var name string
+	var notes string
+	var notesFile string
+	var draft bool
+	var prerelease bool
+	var assets []string
+
+	cmd := &cobra.Command{
+		Use:          "create <tag>",
+		Short:        "Publish a new release",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoURL, err := DetectRepoURL(repo)
+			if err != nil {
+				return err
+			}
+
+			resolvedNotes, err := ResolveNotes(notes, notesFile)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			create := &ReleaseCreate{
+				Config:  config,
+				RepoURL: repoURL,
+				Options: ReleaseCreateOptions{
+					Tag:        args[0],
+					Target:     target,
+					Name:       name,
+					Notes:      resolvedNotes,
+					Draft:      draft,
+					Prerelease: prerelease,
+				},
+				Assets: assets,
+			}
+			_, err = create.Run()
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository to publish to, as owner/name or a full URL (defaults to the current git repo's origin remote)")
+	cmd.Flags().StringVar(&target, "target", "", "branch or commit SHA the tag is created from, if it doesn't already exist")
+	cmd.Flags().StringVar(&name, "name", "", "release title (defaults to the tag)")
+	cmd.Flags().StringVar(&notes, "notes", "", "release notes")
+	cmd.Flags().StringVar(&notesFile, "notes-file", "", "read release notes from this file, or \"-\" for stdin")
+	cmd.Flags().BoolVar(&draft, "draft", false, "create an unpublished draft release")
+	cmd.Flags().BoolVar(&prerelease, "prerelease", false, "mark the release as a prerelease")
+	cmd.Flags().StringArrayVar(&assets, "asset", nil, "asset to upload, as path or path#label, can be repeated")
+
+	return cmd
+}
+
+// releaseListCmd represents the release list command
+func releaseListCmd() *cobra.Command {
+	var repo string
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List releases",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoURL, err := DetectRepoURL(repo)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			publisher, err := GetReleasePublisher(repoURL, config.GetConfig())
+			if err != nil {
+				return err
+			}
+
+			releases, err := publisher.ListReleases()
+			if err != nil {
+				return fmt.Errorf("error listing releases: %v", err)
+			}
+			if len(releases) == 0 {
+				PrintInfo("No releases found")
+				return nil
+			}
+
+			var tableRows []map[string]string
+			for _, release := range releases {
+				tableRows = append(tableRows, map[string]string{
+					"Tag":          release.TagName,
+					"Name":         release.Name,
+					"Draft":        fmt.Sprintf("%t", release.Draft),
+					"Prerelease":   fmt.Sprintf("%t", release.Prerelease),
+					"Published At": release.PublishedAt,
+				})
+			}
+			headers := []string{"Tag", "Name", "Draft", "Prerelease", "Published At"}
+			PrintTable(tableRows, headers, nil)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository to list releases of, as owner/name or a full URL (defaults to the current git repo's origin remote)")
+
+	return cmd
+}
+
+// releaseShowCmd represents the release show command
+func releaseShowCmd() *cobra.Command {
+	var repo string
+
+	cmd := &cobra.Command{
+		Use:          "show <tag>",
+		Short:        "Show a release's details",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoURL, err := DetectRepoURL(repo)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			publisher, err := GetReleasePublisher(repoURL, config.GetConfig())
+			if err != nil {
+				return err
+			}
+
+			release, err := publisher.GetRelease(args[0])
+			if err != nil {
+				return fmt.Errorf("error fetching release: %v", err)
+			}
+
+			fmt.Printf("Tag        : %s\n", release.TagName)
+			fmt.Printf("Name       : %s\n", release.Name)
+			fmt.Printf("Draft      : %t\n", release.Draft)
+			fmt.Printf("Prerelease : %t\n", release.Prerelease)
+			fmt.Printf("Published  : %s\n", release.PublishedAt)
+			fmt.Printf("\n%s\n", release.Body)
+			if len(release.Assets) > 0 {
+				fmt.Println("\nAssets:")
+				for _, asset := range release.Assets {
+					fmt.Printf("  %s (%s)\n", asset.Name, asset.BrowserDownloadURL)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository to read, as owner/name or a full URL (defaults to the current git repo's origin remote)")
+
+	return cmd
+}
+
+// releaseEditCmd represents the release edit command
+func releaseEditCmd() *cobra.Command {
+	var repo string
+	var name string
+	var notes string
+	var notesFile string
+	var draft bool
+	var prerelease bool
+
+	cmd := &cobra.Command{
+		Use:          "edit <tag>",
+		Short:        "Edit an existing release",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoURL, err := DetectRepoURL(repo)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			publisher, err := GetReleasePublisher(repoURL, config.GetConfig())
+			if err != nil {
+				return err
+			}
+
+			var opts ReleaseEditOptions
+			if cmd.Flags().Changed("name") {
+				opts.Name = &name
+			}
+			if cmd.Flags().Changed("notes") || cmd.Flags().Changed("notes-file") {
+				resolvedNotes, err := ResolveNotes(notes, notesFile)
+				if err != nil {
+					return err
+				}
+				opts.Notes = &resolvedNotes
+			}
+			if cmd.Flags().Changed("draft") {
+				opts.Draft = &draft
+			}
+			if cmd.Flags().Changed("prerelease") {
+				opts.Prerelease = &prerelease
+			}
+
+			release, err := publisher.EditRelease(args[0], opts)
+			if err != nil {
+				return fmt.Errorf("error editing release: %v", err)
+			}
+
+			PrintSuccess(fmt.Sprintf("Updated release %s", release.TagName))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository to edit, as owner/name or a full URL (defaults to the current git repo's origin remote)")
+	cmd.Flags().StringVar(&name, "name", "", "new release title")
+	cmd.Flags().StringVar(&notes, "notes", "", "new release notes")
+	cmd.Flags().StringVar(&notesFile, "notes-file", "", "read new release notes from this file, or \"-\" for stdin")
+	cmd.Flags().BoolVar(&draft, "draft", false, "mark the release as an unpublished draft")
+	cmd.Flags().BoolVar(&prerelease, "prerelease", false, "mark the release as a prerelease")
+
+	return cmd
+}
+
+// releaseDeleteCmd represents the release delete command
+func releaseDeleteCmd() *cobra.Command {
+	var repo string
+
+	cmd := &cobra.Command{
+		Use:          "delete <tag>",
+		Short:        "Delete a release",
+		Long:         `Delete a release; this does not remove the underlying git tag`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoURL, err := DetectRepoURL(repo)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			publisher, err := GetReleasePublisher(repoURL, config.GetConfig())
+			if err != nil {
+				return err
+			}
+
+			if err := publisher.DeleteRelease(args[0]); err != nil {
+				return fmt.Errorf("error deleting release: %v", err)
+			}
+
+			PrintSuccess(fmt.Sprintf("Deleted release %s", args[0]))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository to delete from, as owner/name or a full URL (defaults to the current git repo's origin remote)")
+
+	return cmd
+}
+
+// releaseDownloadCmd represents the release download command
+func releaseDownloadCmd() *cobra.Command {
+	var repo string
+	var pattern string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:          "download <tag>",
+		Short:        "Download a release's assets",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoURL, err := DetectRepoURL(repo)
+			if err != nil {
+				return err
+			}
+
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			publisher, err := GetReleasePublisher(repoURL, config.GetConfig())
+			if err != nil {
+				return err
+			}
+
+			release, err := publisher.GetRelease(args[0])
+			if err != nil {
+				return fmt.Errorf("error fetching release: %v", err)
+			}
+
+			dir := output
+			if dir == "" {
+				dir = "."
+			}
+			return DownloadReleaseAssets(release, pattern, dir, StdoutReporter{})
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "repository to download from, as owner/name or a full URL (defaults to the current git repo's origin remote)")
+	cmd.Flags().StringVar(&pattern, "pattern", "", "glob pattern that, if set, only downloads matching assets, e.g. '*-linux-amd64.tar.gz'")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "directory to download assets into (defaults to the current directory)")
+
+	return cmd
+}
+
+// selfUpdateCmd represents the self-update command
+func selfUpdateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "self-update",
+		Short:        "Update install-release itself to the latest release",
+		Long:         `Check the install-release GitHub repository for a newer release and replace the running binary`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := NewConfigManager()
+			if err := config.Load(); err != nil {
+				return fmt.Errorf("error loading config: %v", err)
+			}
+
+			PrintInfo("Checking for a newer install-release release...")
+			updated, newVersion, err := SelfUpdate(cmd.Context(), SelfUpdateOptions{
+				Token:            config.GetToken(),
+				PreRelease:       config.GetPreRelease(),
+				MinisignPubKey:   config.GetMinisignPubKey(),
+				CosignPubKey:     config.GetCosignPubKey(),
+				RequireSignature: config.GetRequireSignature(),
+			})
+			if err != nil {
+				return fmt.Errorf("error updating install-release: %v", err)
+			}
+
+			if !updated {
+				fmt.Printf("Already on the latest version: %s\n", Version)
+				return nil
+			}
+
+			PrintSuccess(fmt.Sprintf("Updated install-release: %s => %s", Version, newVersion))
+			return nil
+		},
+	}
 
 	return cmd
 }
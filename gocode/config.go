@@ -11,11 +11,8 @@ import (
 type ConfigManager struct {
 	config     *ToolConfig
 	configFile string
-}
-
-// ConfigWrapper represents the Python-style config structure
-type ConfigWrapper struct {
-	Config *ToolConfig `json:"config"`
+	// migrated records whether the last Load() upgraded the on-disk schema
+	migrated bool
 }
 
 // NewConfigManager creates a new config manager
@@ -29,43 +26,69 @@ func NewConfigManager() *ConfigManager {
 	}
 
 	return &ConfigManager{
-		config:     &ToolConfig{},
+		config:     &ToolConfig{SchemaVersion: CurrentConfigSchemaVersion},
 		configFile: configFile,
 	}
 }
 
-// Load loads the configuration from file
+// Load loads the configuration from file, migrating it up to
+// CurrentConfigSchemaVersion if it predates that field (this also replaces
+// the old ad-hoc probing between a flat ToolConfig and the wrapped
+// Python-style {"config": {...}} shape with a single explicit migration)
 func (cm *ConfigManager) Load() error {
 	if _, err := os.Stat(cm.configFile); os.IsNotExist(err) {
 		// File doesn't exist, start with default config
 		return nil
 	}
 
-	file, err := os.Open(cm.configFile)
+	data, err := os.ReadFile(cm.configFile)
 	if err != nil {
 		return fmt.Errorf("error opening config file: %v", err)
 	}
-	defer file.Close()
 
-	// Try to decode as Python-style config first
-	var wrapper ConfigWrapper
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&wrapper); err == nil && wrapper.Config != nil {
-		// Python-style config found
-		cm.config = wrapper.Config
-		return nil
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("error decoding config file: %v", err)
 	}
 
-	// Reset file pointer and try direct decoding
-	file.Seek(0, 0)
-	decoder = json.NewDecoder(file)
-	if err := decoder.Decode(cm.config); err != nil {
-		return fmt.Errorf("error decoding config file: %v", err)
+	raw, migrated, err := runMigrations(raw, configMigrations, CurrentConfigSchemaVersion)
+	if err != nil {
+		return fmt.Errorf("error migrating config file: %v", err)
+	}
+
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("error re-encoding config: %v", err)
+	}
+
+	var config ToolConfig
+	if err := json.Unmarshal(normalized, &config); err != nil {
+		return fmt.Errorf("error decoding config: %v", err)
+	}
+	cm.config = &config
+	cm.migrated = migrated
+
+	if migrated {
+		if err := backupFile(cm.configFile); err != nil {
+			return err
+		}
+		if err := cm.Save(); err != nil {
+			return fmt.Errorf("error saving migrated config: %v", err)
+		}
 	}
 
 	return nil
 }
 
+// Migrate runs Load (which migrates as a side effect) and reports whether
+// the on-disk config was upgraded, backing `ir config migrate`
+func (cm *ConfigManager) Migrate() (migrated bool, err error) {
+	if err := cm.Load(); err != nil {
+		return false, err
+	}
+	return cm.migrated, nil
+}
+
 // Save saves the configuration to file
 func (cm *ConfigManager) Save() error {
 	file, err := os.Create(cm.configFile)
@@ -130,7 +153,246 @@ func (cm *ConfigManager) SetPreRelease(preRelease bool) {
 	cm.Save()
 }
 
+// GetMinisignPubKey returns the configured minisign public key
+func (cm *ConfigManager) GetMinisignPubKey() string {
+	return cm.config.MinisignPubKey
+}
+
+// SetMinisignPubKey sets the minisign public key used to verify release signatures
+func (cm *ConfigManager) SetMinisignPubKey(key string) {
+	cm.config.MinisignPubKey = key
+	cm.Save()
+}
+
+// GetCosignPubKey returns the configured cosign public key
+func (cm *ConfigManager) GetCosignPubKey() string {
+	return cm.config.CosignPubKey
+}
+
+// SetCosignPubKey sets the cosign public key used to verify release signatures
+func (cm *ConfigManager) SetCosignPubKey(key string) {
+	cm.config.CosignPubKey = key
+	cm.Save()
+}
+
+// GetRequireSignature returns whether a missing signature should be a hard error
+func (cm *ConfigManager) GetRequireSignature() bool {
+	return cm.config.RequireSignature
+}
+
+// SetRequireSignature sets whether a missing signature should be a hard error
+func (cm *ConfigManager) SetRequireSignature(require bool) {
+	cm.config.RequireSignature = require
+	cm.Save()
+}
+
+// GetGithubEnterpriseHosts returns the whitelisted GitHub Enterprise hosts
+func (cm *ConfigManager) GetGithubEnterpriseHosts() []string {
+	return cm.config.GithubEnterpriseHosts
+}
+
+// AddGithubEnterpriseHost whitelists a GitHub Enterprise host
+func (cm *ConfigManager) AddGithubEnterpriseHost(host string) {
+	cm.config.GithubEnterpriseHosts = append(cm.config.GithubEnterpriseHosts, host)
+	cm.Save()
+}
+
+// GetGitlabHosts returns the whitelisted self-hosted GitLab hosts
+func (cm *ConfigManager) GetGitlabHosts() []string {
+	return cm.config.GitlabHosts
+}
+
+// AddGitlabHost whitelists a self-hosted GitLab host
+func (cm *ConfigManager) AddGitlabHost(host string) {
+	cm.config.GitlabHosts = append(cm.config.GitlabHosts, host)
+	cm.Save()
+}
+
+// GetGiteaHosts returns the whitelisted Gitea/Forgejo hosts
+func (cm *ConfigManager) GetGiteaHosts() []string {
+	return cm.config.GiteaHosts
+}
+
+// AddGiteaHost whitelists a self-hosted Gitea/Forgejo host
+func (cm *ConfigManager) AddGiteaHost(host string) {
+	cm.config.GiteaHosts = append(cm.config.GiteaHosts, host)
+	cm.Save()
+}
+
+// GetURLTemplate returns the URL template pinned for repoURL, used by
+// GetRepoInfo to install from a forge/mirror with no dedicated API support
+func (cm *ConfigManager) GetURLTemplate(repoURL string) string {
+	if cm.config.URLTemplates == nil {
+		return ""
+	}
+	return cm.config.URLTemplates[repoURL]
+}
+
+// SetURLTemplate pins a download URL template for repoURL, a Go text/template
+// string with .Version, .OS and .Arch variables, e.g.
+// "https://example.com/releases/{{.Version}}/tool_{{.OS}}_{{.Arch}}.tar.gz"
+func (cm *ConfigManager) SetURLTemplate(repoURL, tmpl string) {
+	if cm.config.URLTemplates == nil {
+		cm.config.URLTemplates = make(map[string]string)
+	}
+	cm.config.URLTemplates[repoURL] = tmpl
+	cm.Save()
+}
+
+// SetHostToken sets a per-host token, used instead of the plain GitHub/GitLab
+// token when talking to that host
+func (cm *ConfigManager) SetHostToken(host, token string) {
+	if cm.config.Tokens == nil {
+		cm.config.Tokens = make(map[string]string)
+	}
+	cm.config.Tokens[host] = token
+	cm.Save()
+}
+
+// GetTrustedKey returns the minisign/cosign public key pinned for repoURL,
+// used when verifying a release with no global MinisignPubKey/CosignPubKey set
+func (cm *ConfigManager) GetTrustedKey(repoURL string) string {
+	if cm.config.TrustedKeys == nil {
+		return ""
+	}
+	return cm.config.TrustedKeys[repoURL]
+}
+
+// SetTrustedKey pins a minisign/cosign public key for repoURL
+func (cm *ConfigManager) SetTrustedKey(repoURL, pubKey string) {
+	if cm.config.TrustedKeys == nil {
+		cm.config.TrustedKeys = make(map[string]string)
+	}
+	cm.config.TrustedKeys[repoURL] = pubKey
+	cm.Save()
+}
+
+// GetMustMatch returns the default asset-selector must-match patterns
+func (cm *ConfigManager) GetMustMatch() []string {
+	return cm.config.MustMatch
+}
+
+// AddMustMatch adds a default asset-selector must-match pattern
+func (cm *ConfigManager) AddMustMatch(pattern string) {
+	cm.config.MustMatch = append(cm.config.MustMatch, pattern)
+	cm.Save()
+}
+
+// GetMustNotMatch returns the default asset-selector must-not-match patterns
+func (cm *ConfigManager) GetMustNotMatch() []string {
+	return cm.config.MustNotMatch
+}
+
+// AddMustNotMatch adds a default asset-selector must-not-match pattern
+func (cm *ConfigManager) AddMustNotMatch(pattern string) {
+	cm.config.MustNotMatch = append(cm.config.MustNotMatch, pattern)
+	cm.Save()
+}
+
+// GetPreferExtensions returns the default asset-selector extension preference order
+func (cm *ConfigManager) GetPreferExtensions() []string {
+	return cm.config.PreferExtensions
+}
+
+// AddPreferExtensions adds an extension to the default asset-selector preference order
+func (cm *ConfigManager) AddPreferExtensions(ext string) {
+	cm.config.PreferExtensions = append(cm.config.PreferExtensions, ext)
+	cm.Save()
+}
+
 // GetConfig returns the entire config
 func (cm *ConfigManager) GetConfig() *ToolConfig {
 	return cm.config
 }
+
+// GetAssetOverride returns the asset-selection override pinned for repoURL,
+// or nil if none is configured
+func (cm *ConfigManager) GetAssetOverride(repoURL string) *RepoOverride {
+	if cm.config.AssetOverrides == nil {
+		return nil
+	}
+	if override, ok := cm.config.AssetOverrides[repoURL]; ok {
+		return &override
+	}
+	return nil
+}
+
+// SetAssetOverride pins an asset-selection override (os/arch/libc/pattern)
+// for repoURL, so get/upgrade keep picking the same variant for that repo
+func (cm *ConfigManager) SetAssetOverride(repoURL string, override RepoOverride) {
+	if cm.config.AssetOverrides == nil {
+		cm.config.AssetOverrides = make(map[string]RepoOverride)
+	}
+	cm.config.AssetOverrides[repoURL] = override
+	cm.Save()
+}
+
+// GetStateBackend returns the configured StateDriver backend name (one of
+// StateBackendFile, StateBackendDir, StateBackendSQLite), defaulting to
+// StateBackendFile when unset
+func (cm *ConfigManager) GetStateBackend() string {
+	if cm.config.StateBackend == "" {
+		return StateBackendFile
+	}
+	return cm.config.StateBackend
+}
+
+// SetStateBackend pins the StateDriver backend used to persist installed
+// tool state; takes effect on the next command invocation since the backend
+// is chosen when NewStateManager() opens the store
+func (cm *ConfigManager) SetStateBackend(backend string) error {
+	switch backend {
+	case StateBackendFile, StateBackendDir, StateBackendSQLite:
+		cm.config.StateBackend = backend
+		cm.Save()
+		return nil
+	default:
+		return fmt.Errorf("unknown state backend %q, expected one of: file, dir, sqlite", backend)
+	}
+}
+
+// GetHooks returns the global lifecycle hook set, overridden per-tool by a
+// Release's own Hooks field (see ResolveHooks)
+func (cm *ConfigManager) GetHooks() HookSet {
+	return cm.config.Hooks
+}
+
+// AddHook appends a shell command to the global hook list for event
+func (cm *ConfigManager) AddHook(event, command string) error {
+	switch event {
+	case "pre_install":
+		cm.config.Hooks.PreInstall = append(cm.config.Hooks.PreInstall, command)
+	case "post_install":
+		cm.config.Hooks.PostInstall = append(cm.config.Hooks.PostInstall, command)
+	case "pre_upgrade":
+		cm.config.Hooks.PreUpgrade = append(cm.config.Hooks.PreUpgrade, command)
+	case "post_upgrade":
+		cm.config.Hooks.PostUpgrade = append(cm.config.Hooks.PostUpgrade, command)
+	case "pre_remove":
+		cm.config.Hooks.PreRemove = append(cm.config.Hooks.PreRemove, command)
+	case "post_remove":
+		cm.config.Hooks.PostRemove = append(cm.config.Hooks.PostRemove, command)
+	default:
+		return fmt.Errorf("unknown hook event %q, expected one of: pre_install, post_install, pre_upgrade, post_upgrade, pre_remove, post_remove", event)
+	}
+	cm.Save()
+	return nil
+}
+
+// GetNotify returns the configured webhook/Slack notification targets
+func (cm *ConfigManager) GetNotify() NotifyConfig {
+	return cm.config.Notify
+}
+
+// SetNotifyWebhook sets the generic webhook URL POSTed a JSON payload on
+// every hook-bearing lifecycle event
+func (cm *ConfigManager) SetNotifyWebhook(url string) {
+	cm.config.Notify.WebhookURL = url
+	cm.Save()
+}
+
+// SetNotifySlack sets the Slack incoming-webhook URL notified the same way
+func (cm *ConfigManager) SetNotifySlack(url string) {
+	cm.config.Notify.SlackURL = url
+	cm.Save()
+}
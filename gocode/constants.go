@@ -6,6 +6,37 @@ import (
 	"runtime"
 )
 
+// Version is the install-release version, set at build time via -ldflags
+var Version = "v0.5.2"
+
+// ArchiveExtensions lists the archive file suffixes the extractor knows how
+// to unpack; isArchive/isArchiveAsset match a name against any of them
+var ArchiveExtensions = []string{
+	".tar.gz", ".tgz",
+	".tar.bz2", ".tbz2",
+	".tar.xz", ".txz",
+	".tar.zst", ".tzst",
+	".zip",
+	".7z",
+	".tar",
+	".gz",
+	".xz",
+}
+
+// SelfRepoURL is the GitHub repository the tool updates itself from
+const SelfRepoURL = "https://github.com/Rishang/install-release"
+
+// MaxHistoryRevisions caps how many HistoryRevision entries are kept per
+// tool; AppendHistory trims the oldest once this is exceeded
+const MaxHistoryRevisions = 5
+
+// StateDriver backend names, set via `ir config --state-backend`
+const (
+	StateBackendFile   = "file"
+	StateBackendDir    = "dir"
+	StateBackendSQLite = "sqlite"
+)
+
 var (
 	HOME = func() string {
 		home := os.Getenv("HOME")
@@ -15,12 +46,60 @@ var (
 		return home
 	}()
 
-	BinAt    = "bin"
-	DirName  = "install_release"
-	StateAt  = filepath.Join(DirName, "state.json")
-	ConfigAt = filepath.Join(DirName, "config.json")
+	BinAt     = "bin"
+	DirName   = "install_release"
+	StateAt   = filepath.Join(DirName, "state.json")
+	StateDAt  = filepath.Join(DirName, "state.d")
+	StateDBAt = filepath.Join(DirName, "state.db")
+	ConfigAt  = filepath.Join(DirName, "config.json")
 )
 
+// xdgHome returns the value of envVar if set, or filepath.Join(HOME, fallback...) otherwise
+func xdgHome(envVar string, fallback ...string) string {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir
+	}
+	return filepath.Join(append([]string{HOME}, fallback...)...)
+}
+
+// xdgConfigHome resolves $XDG_CONFIG_HOME, defaulting to ~/.config
+func xdgConfigHome() string {
+	return xdgHome("XDG_CONFIG_HOME", ".config")
+}
+
+// xdgStateHome resolves $XDG_STATE_HOME, defaulting to ~/.local/state
+func xdgStateHome() string {
+	return xdgHome("XDG_STATE_HOME", ".local", "state")
+}
+
+// xdgCacheHome resolves $XDG_CACHE_HOME, defaulting to ~/.cache
+func xdgCacheHome() string {
+	return xdgHome("XDG_CACHE_HOME", ".cache")
+}
+
+// xdgDataHome resolves $XDG_DATA_HOME, defaulting to ~/.local/share; this is
+// where shell completions and man pages installed alongside a tool live
+func xdgDataHome() string {
+	return xdgHome("XDG_DATA_HOME", ".local", "share")
+}
+
+// localAppData resolves %LOCALAPPDATA% on Windows, used for state and for a
+// user-writable Programs directory that doesn't require admin rights
+func localAppData() string {
+	if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+		return dir
+	}
+	return filepath.Join(HOME, "AppData", "Local")
+}
+
+// roamingAppData resolves %APPDATA% on Windows, used for config
+func roamingAppData() string {
+	if dir := os.Getenv("APPDATA"); dir != "" {
+		return dir
+	}
+	return filepath.Join(HOME, "AppData", "Roaming")
+}
+
 // Colors for terminal output
 var Colors = map[string]string{
 	"green":       "#8CC265",
@@ -32,20 +111,43 @@ var Colors = map[string]string{
 	"purple":      "#8782E9 bold",
 }
 
-// Platform paths mapping - matches Python version structure
+// Platform paths mapping. Linux follows the XDG base directory spec; macOS
+// uses the standard ~/Library/Application Support; Windows uses
+// %LOCALAPPDATA%/%APPDATA%, the user-writable equivalents of /config and /state.
 var StatePaths = map[string]string{
-	"linux":  filepath.Join(HOME, ".config", StateAt),
-	"darwin": filepath.Join(HOME, "Library", ".config", StateAt),
+	"linux":   filepath.Join(xdgStateHome(), StateAt),
+	"darwin":  filepath.Join(HOME, "Library", "Application Support", StateAt),
+	"windows": filepath.Join(localAppData(), StateAt),
 }
 
 var ConfigPaths = map[string]string{
-	"linux":  filepath.Join(HOME, ".config", ConfigAt),
-	"darwin": filepath.Join(HOME, "Library", ".config", ConfigAt),
+	"linux":   filepath.Join(xdgConfigHome(), ConfigAt),
+	"darwin":  filepath.Join(HOME, "Library", "Application Support", ConfigAt),
+	"windows": filepath.Join(roamingAppData(), ConfigAt),
+}
+
+var StateDirPaths = map[string]string{
+	"linux":   filepath.Join(xdgStateHome(), StateDAt),
+	"darwin":  filepath.Join(HOME, "Library", "Application Support", StateDAt),
+	"windows": filepath.Join(localAppData(), StateDAt),
+}
+
+var StateDBPaths = map[string]string{
+	"linux":   filepath.Join(xdgStateHome(), StateDBAt),
+	"darwin":  filepath.Join(HOME, "Library", "Application Support", StateDBAt),
+	"windows": filepath.Join(localAppData(), StateDBAt),
 }
 
 var BinPaths = map[string]string{
-	"linux":  filepath.Join(HOME, BinAt),
-	"darwin": filepath.Join(HOME, BinAt),
+	"linux":   filepath.Join(HOME, BinAt),
+	"darwin":  filepath.Join(HOME, BinAt),
+	"windows": filepath.Join(localAppData(), "Programs", DirName, BinAt),
+}
+
+var CachePaths = map[string]string{
+	"linux":   filepath.Join(xdgCacheHome(), DirName),
+	"darwin":  filepath.Join(HOME, "Library", "Caches", DirName),
+	"windows": filepath.Join(localAppData(), DirName, "cache"),
 }
 
 // PlatformPath provides path based on platform - matches Python version logic
@@ -67,8 +169,8 @@ func PlatformPath(paths map[string]string, alt string) string {
 		}
 		return path
 	} else {
-		// Return a default path instead of exiting
-		return filepath.Join(HOME, ".config", StateAt)
+		// Unknown platform: fall back to the XDG-style default
+		return filepath.Join(xdgConfigHome(), StateAt)
 	}
 }
 
@@ -82,11 +184,28 @@ func ConfigPath() string {
 	return PlatformPath(ConfigPaths, "")
 }
 
+// StateDirPath returns the directory used by the "dir" StateDriver, one
+// JSON file per installed tool
+func StateDirPath() string {
+	return PlatformPath(StateDirPaths, "")
+}
+
+// StateDBPath returns the SQLite database file used by the "sqlite" StateDriver
+func StateDBPath() string {
+	return PlatformPath(StateDBPaths, "")
+}
+
 // BinPath returns the binary installation path for the current platform
 func BinPath() string {
 	return PlatformPath(BinPaths, "")
 }
 
+// CacheDirPath returns the download cache directory for the current
+// platform, under which Downloader stores content-addressed blobs
+func CacheDirPath() string {
+	return PlatformPath(CachePaths, "")
+}
+
 // getSystem returns the current operating system
 func getSystem() string {
 	// Use runtime.GOOS instead of env var for more reliable detection
@@ -56,34 +56,29 @@ func (ir *InstallRelease) Install(local bool, at string) error {
 	return nil
 }
 
-// GetRelease finds the best release asset for the current system
+// GetRelease finds the best release asset for the current system. It is a
+// thin wrapper around the default AssetSelector so existing callers keep
+// their original behavior; see GetReleaseWithSelector for configurable
+// pattern-based selection.
 func GetRelease(releases []*Release, repoURL string, extraWords []string) (*ReleaseAssets, error) {
 	if len(releases) == 0 {
 		return nil, fmt.Errorf("no releases found")
 	}
 
-	// Get system information
 	os, arch := GetSystemInfo()
+	return defaultAssetSelector(extraWords).Select(releases, os, arch)
+}
 
-	// Find the best matching asset
-	var bestAsset *ReleaseAssets
-	var bestScore float64
-
-	for _, release := range releases {
-		for _, asset := range release.Assets {
-			score := calculateAssetScore(asset, os, arch, extraWords)
-			if score > bestScore {
-				bestScore = score
-				bestAsset = &asset
-			}
-		}
-	}
-
-	if bestAsset == nil {
-		return nil, fmt.Errorf("no suitable asset found for %s/%s", os, arch)
+// GetReleaseWithSelector finds the best release asset for the current
+// system using a caller-supplied AssetSelector, allowing MustMatch,
+// MustNotMatch and Pick rules to override the default heuristic
+func GetReleaseWithSelector(releases []*Release, selector *AssetSelector) (*ReleaseAssets, error) {
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no releases found")
 	}
 
-	return bestAsset, nil
+	os, arch := GetSystemInfo()
+	return selector.Select(releases, os, arch)
 }
 
 // calculateAssetScore calculates a score for how well an asset matches the system
@@ -98,7 +93,7 @@ func calculateAssetScore(asset ReleaseAssets, os, arch string, extraWords []stri
 	allPatterns := append(platformPatterns, extraWords...)
 
 	// Add archive patterns for better matching
-	allPatterns = append(allPatterns, "tar", "zip")
+	allPatterns = append(allPatterns, "tar", "zip", "bz2", "xz", "zst", "7z")
 
 	// Calculate pattern match score
 	matchCount := 0
@@ -168,11 +163,8 @@ func applyPlatformPenalties(score float64, asset ReleaseAssets, currentOS, curre
 func getPlatformPatterns(osName, arch string) []string {
 	patterns := []string{strings.ToLower(osName)}
 
-	// Architecture aliases
-	archAliases := map[string][]string{
-		"x86_64":  {"x86", "x64", "amd64", "amd", "x86_64"},
-		"aarch64": {"arm64", "aarch64", "arm"},
-	}
+	// Architecture aliases, overridable per-machine via ~/.install-release/aliases.yaml
+	archAliases := loadArchAliases()
 
 	// Add architecture patterns
 	if aliases, exists := archAliases[arch]; exists {
@@ -242,9 +234,8 @@ func isExecutableAsset(asset ReleaseAssets) bool {
 // isArchiveAsset checks if an asset is an archive
 func isArchiveAsset(asset ReleaseAssets) bool {
 	name := strings.ToLower(asset.Name)
-	archiveExtensions := []string{".tar.gz", ".tgz", ".zip", ".tar"}
 
-	for _, ext := range archiveExtensions {
+	for _, ext := range ArchiveExtensions {
 		if strings.HasSuffix(name, ext) {
 			return true
 		}
@@ -267,49 +258,76 @@ func isPackageAsset(asset ReleaseAssets) bool {
 	return false
 }
 
-// ExtractRelease extracts a release asset
-func ExtractRelease(asset *ReleaseAssets, extractPath string) error {
+// ExtractRelease extracts a release asset, verifying it against any
+// checksum manifest or signature published alongside it in release. It
+// returns the asset's verified sha256 digest (empty if verification was
+// skipped) so callers can record it in state for tamper detection
+func ExtractRelease(asset *ReleaseAssets, release *Release, extractPath string, verifyOpts VerifyOptions) (string, error) {
+	return extractRelease(NewDownloader(), asset, release, extractPath, verifyOpts)
+}
+
+// extractRelease is ExtractRelease with an injectable downloader, so tests
+// can point it at a temp cache directory instead of the real one
+func extractRelease(downloader *Downloader, asset *ReleaseAssets, release *Release, extractPath string, verifyOpts VerifyOptions) (string, error) {
 	// Show download information using proper info logging
 	PrintInfo(fmt.Sprintf("Downloading: %s (%.1f MB)", asset.Name, float64(asset.Size)/1024/1024))
 
-	// Create temporary directory for download
-	tempDir := filepath.Join(GetTempDir(), "install-release")
-	if err := Mkdir(tempDir); err != nil {
-		return fmt.Errorf("error creating temp directory: %v", err)
+	// Learn the asset's expected digest ahead of time, if a checksum
+	// manifest is published alongside it, so the download cache can skip
+	// the network entirely on a hit
+	var expectedDigest string
+	if !verifyOpts.Skip {
+		if checksumAsset := findChecksumAsset(asset, release); checksumAsset != nil {
+			if digest, err := downloadChecksumEntry(checksumAsset, asset.Name); err == nil {
+				expectedDigest = digest
+			}
+		}
 	}
 
-	// Download the asset
-	downloadPath := filepath.Join(tempDir, asset.Name)
-	if err := Download(asset.BrowserDownloadURL, downloadPath); err != nil {
-		return fmt.Errorf("error downloading asset: %v", err)
+	downloadPath, err := downloader.Fetch(asset.BrowserDownloadURL, FetchOptions{ExpectedDigest: expectedDigest, Parallel: 4})
+	if err != nil {
+		return "", fmt.Errorf("error downloading asset: %v", err)
 	}
 
 	PrintSuccess(fmt.Sprintf("Downloaded: %s", asset.Name))
 
+	digest, err := VerifyAsset(asset, release, downloadPath, verifyOpts)
+	if err != nil {
+		return "", fmt.Errorf("error verifying asset: %v", err)
+	}
+
 	// Extract if it's an archive
 	if isArchive(asset.Name) {
 		PrintInfo(fmt.Sprintf("Extracting: %s", asset.Name))
-		if err := Extract(downloadPath, extractPath); err != nil {
-			return fmt.Errorf("error extracting asset: %v", err)
+
+		// downloadPath is a content-addressed cache blob named by its sha256
+		// digest, with no file extension, but Extract dispatches on filename
+		// suffix - hand it a copy named after the asset instead of the bare
+		// blob path
+		namedPath, cleanup, err := namedAssetCopy(downloadPath, asset.Name)
+		if err != nil {
+			return "", fmt.Errorf("error preparing asset for extraction: %v", err)
+		}
+		defer cleanup()
+
+		if err := Extract(namedPath, extractPath); err != nil {
+			return "", fmt.Errorf("error extracting asset: %v", err)
 		}
 	} else {
 		// Copy the file directly
 		destPath := filepath.Join(extractPath, asset.Name)
 		if err := CopyFile(downloadPath, destPath); err != nil {
-			return fmt.Errorf("error copying asset: %v", err)
+			return "", fmt.Errorf("error copying asset: %v", err)
 		}
 	}
 
-	// Clean up downloaded file
-	RemoveFile(downloadPath)
-
-	return nil
+	return digest, nil
 }
 
 // isArchive checks if a file is an archive
 func isArchive(filename string) bool {
-	archiveExtensions := []string{".tar.gz", ".tgz", ".zip", ".tar"}
-	for _, ext := range archiveExtensions {
+	filename = strings.ToLower(filename)
+	for _, ext := range ArchiveExtensions {
 		if strings.HasSuffix(filename, ext) {
 			return true
 		}
@@ -339,20 +357,15 @@ func InstallBin(src, dest string, local bool, name string) error {
 	return nil
 }
 
-// FindBestAsset finds the best asset for the current system
+// FindBestAsset finds the best asset for the current system. It is a thin
+// wrapper around the default AssetSelector; see GetReleaseWithSelector for
+// configurable pattern-based selection.
 func FindBestAsset(assets []ReleaseAssets, os, arch string, extraWords []string) *ReleaseAssets {
-	var bestAsset *ReleaseAssets
-	var bestScore float64
-
-	for _, asset := range assets {
-		score := calculateAssetScore(asset, os, arch, extraWords)
-		if score > bestScore {
-			bestScore = score
-			bestAsset = &asset
-		}
+	asset, err := defaultAssetSelector(extraWords).SelectAssets(assets, os, arch)
+	if err != nil {
+		return nil
 	}
-
-	return bestAsset
+	return asset
 }
 
 // GetExecutablePattern returns a regex pattern for finding executables
@@ -370,5 +383,8 @@ func IsExecutableMimeType(mimeType string) bool {
 func GetExceptionCompressedMimeTypes() []string {
 	return []string{
 		"application/x-7z-compressed",
+		"application/x-bzip2",
+		"application/x-xz",
+		"application/zstd",
 	}
 }
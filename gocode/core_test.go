@@ -0,0 +1,75 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestExtractReleaseArchiveDispatch runs extractRelease against a real
+// .tar.gz fixture served over HTTP and fetched through a Downloader's
+// content-addressable cache, guarding against a regression where Fetch's
+// extension-less blob path (<cache>/blobs/sha256/<digest>) was handed
+// straight to Extract, which dispatches the archive format purely on
+// filename suffix and so rejected every cached download with "unsupported
+// archive format"
+func TestExtractReleaseArchiveDispatch(t *testing.T) {
+	const fileName = "hello.txt"
+	const fileContent = "hello from the archive\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(buildTarGz(t, fileName, fileContent))
+	}))
+	defer server.Close()
+
+	asset := &ReleaseAssets{
+		Name:               "tool-linux-amd64.tar.gz",
+		BrowserDownloadURL: server.URL,
+	}
+	release := &Release{TagName: "v1.0.0", Assets: []ReleaseAssets{*asset}}
+
+	downloader := &Downloader{CacheDir: t.TempDir()}
+	extractPath := t.TempDir()
+
+	if _, err := extractRelease(downloader, asset, release, extractPath, VerifyOptions{Skip: true}); err != nil {
+		t.Fatalf("extractRelease: %v", err)
+	}
+
+	extracted := filepath.Join(extractPath, fileName)
+	data, err := os.ReadFile(extracted)
+	if err != nil {
+		t.Fatalf("reading extracted file: %v", err)
+	}
+	if string(data) != fileContent {
+		t.Fatalf("extracted file content = %q, want %q", data, fileContent)
+	}
+}
+
+// buildTarGz builds an in-memory .tar.gz archive containing a single file
+func buildTarGz(t *testing.T, name, content string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+		t.Fatalf("writing tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatalf("writing tar content: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("closing tar writer: %v", err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("closing gzip writer: %v", err)
+	}
+
+	return buf.Bytes()
+}
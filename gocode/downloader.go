@@ -0,0 +1,331 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// parallelDownloadThreshold is the minimum declared content length above
+// which Fetch splits a download across FetchOptions.Parallel concurrent
+// range requests
+const parallelDownloadThreshold = 50 * 1024 * 1024 // 50 MB
+
+// Downloader fetches release assets into a content-addressable cache under
+// CacheDirPath()/blobs/sha256/<digest>, so re-installing or upgrading the
+// same asset (e.g. after a rollback, or across machines sharing a cache
+// mount) reuses the already-downloaded, already-verified blob instead of
+// hitting the network again
+type Downloader struct {
+	CacheDir string
+
+	mu sync.Mutex
+}
+
+// FetchOptions controls how Downloader.Fetch retrieves a URL
+type FetchOptions struct {
+	// ExpectedDigest, if set, lets Fetch return a cached blob with no
+	// network request at all, and is checked against the downloaded
+	// content when a download does happen
+	ExpectedDigest string
+	// Parallel is the number of concurrent range requests to use for
+	// downloads above parallelDownloadThreshold; 0 or 1 disables chunking
+	Parallel int
+}
+
+// cacheIndexEntry records the ETag and content digest Fetch last saw for a URL
+type cacheIndexEntry struct {
+	ETag   string `json:"etag,omitempty"`
+	Digest string `json:"digest"`
+}
+
+// NewDownloader creates a Downloader backed by the default cache directory
+func NewDownloader() *Downloader {
+	return &Downloader{CacheDir: CacheDirPath()}
+}
+
+func (d *Downloader) blobPath(digest string) string {
+	return filepath.Join(d.CacheDir, "blobs", "sha256", digest)
+}
+
+func (d *Downloader) indexPath() string {
+	return filepath.Join(d.CacheDir, "index.json")
+}
+
+// Fetch downloads url, returning the path to its content-addressed cache
+// entry. A cache hit skips the network entirely when opts.ExpectedDigest is
+// already present, and skips re-downloading the body (via a conditional
+// If-None-Match request) when the URL's ETag still matches what was cached
+// last time.
+func (d *Downloader) Fetch(url string, opts FetchOptions) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if opts.ExpectedDigest != "" {
+		if path := d.blobPath(opts.ExpectedDigest); Exists(path) {
+			return path, nil
+		}
+	}
+
+	index := d.loadIndex()
+	if entry, ok := index[url]; ok && Exists(d.blobPath(entry.Digest)) {
+		if d.revalidate(url, entry.ETag) {
+			return d.blobPath(entry.Digest), nil
+		}
+	}
+
+	partPath := filepath.Join(d.CacheDir, "tmp", fmt.Sprintf("%x.part", sha256.Sum256([]byte(url))))
+	if err := Mkdir(filepath.Dir(partPath)); err != nil {
+		return "", fmt.Errorf("error creating cache temp directory: %v", err)
+	}
+
+	etag, err := d.download(url, partPath, opts)
+	if err != nil {
+		return "", err
+	}
+
+	digest, err := sha256File(partPath)
+	if err != nil {
+		return "", fmt.Errorf("error hashing downloaded file: %v", err)
+	}
+
+	if opts.ExpectedDigest != "" && !strings.EqualFold(digest, opts.ExpectedDigest) {
+		RemoveFile(partPath)
+		return "", fmt.Errorf("checksum mismatch for %s: expected %s, got %s", url, opts.ExpectedDigest, digest)
+	}
+
+	blobPath := d.blobPath(digest)
+	if err := Mkdir(filepath.Dir(blobPath)); err != nil {
+		return "", fmt.Errorf("error creating blob directory: %v", err)
+	}
+	if err := os.Rename(partPath, blobPath); err != nil {
+		return "", fmt.Errorf("error caching downloaded file: %v", err)
+	}
+
+	index[url] = cacheIndexEntry{ETag: etag, Digest: digest}
+	if err := d.saveIndex(index); err != nil {
+		PrintError(fmt.Sprintf("error saving download cache index: %v", err))
+	}
+
+	return blobPath, nil
+}
+
+// download fetches url into partPath, resuming from any partial content
+// already there via a Range request, and splitting the transfer across
+// opts.Parallel concurrent range requests once the server's declared size
+// clears parallelDownloadThreshold. It returns the response's ETag.
+func (d *Downloader) download(url, partPath string, opts FetchOptions) (string, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	head, err := client.Head(url)
+	var size int64 = -1
+	etag := ""
+	acceptsRanges := false
+	if err == nil {
+		head.Body.Close()
+		size = head.ContentLength
+		etag = head.Header.Get("ETag")
+		acceptsRanges = head.Header.Get("Accept-Ranges") == "bytes"
+	}
+
+	if opts.Parallel > 1 && acceptsRanges && size > parallelDownloadThreshold {
+		if err := d.downloadParallel(url, partPath, size, opts.Parallel); err == nil {
+			return etag, nil
+		}
+		// Fall through to a single-stream download on any chunked-download
+		// error. downloadParallel preallocates partPath to the full size, so
+		// it must be removed here or downloadResumable would see a
+		// full-length file and send an already-satisfied Range request.
+		RemoveFile(partPath)
+	}
+
+	if err := d.downloadResumable(url, partPath); err != nil {
+		return "", err
+	}
+
+	return etag, nil
+}
+
+// downloadResumable appends to any existing partial file at partPath via a
+// Range request, so an interrupted download over a flaky connection picks
+// up where it left off instead of restarting
+func (d *Downloader) downloadResumable(url, partPath string) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error building request: %v", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := &http.Client{Timeout: 0}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error downloading file: %v", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+	case http.StatusOK:
+		// Server ignored the Range request (or there was nothing to
+		// resume); start over
+		flags |= os.O_TRUNC
+	default:
+		return fmt.Errorf("download failed with status: %d", resp.StatusCode)
+	}
+
+	file, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return fmt.Errorf("error writing file: %v", err)
+	}
+
+	return nil
+}
+
+// downloadParallel splits [0,size) into parallel equal ranges, downloads
+// them concurrently, and writes each chunk directly to its offset in partPath
+func (d *Downloader) downloadParallel(url, partPath string, size int64, parallel int) error {
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	if err := file.Truncate(size); err != nil {
+		file.Close()
+		return fmt.Errorf("error preallocating file: %v", err)
+	}
+	file.Close()
+
+	chunkSize := size / int64(parallel)
+	var wg sync.WaitGroup
+	errs := make([]error, parallel)
+
+	for i := 0; i < parallel; i++ {
+		start := int64(i) * chunkSize
+		end := start + chunkSize - 1
+		if i == parallel-1 {
+			end = size - 1
+		}
+
+		wg.Add(1)
+		go func(i int, start, end int64) {
+			defer wg.Done()
+			errs[i] = d.downloadChunk(url, partPath, start, end)
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadChunk fetches the byte range [start, end] of url and writes it at
+// the matching offset in the file at path
+func (d *Downloader) downloadChunk(url, path string, start, end int64) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error building request: %v", err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	client := &http.Client{Timeout: 0}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error downloading chunk: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("chunk download failed with status: %d", resp.StatusCode)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening file: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return fmt.Errorf("error seeking file: %v", err)
+	}
+
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		return fmt.Errorf("error writing chunk: %v", err)
+	}
+
+	return nil
+}
+
+// revalidate sends a conditional HEAD request with If-None-Match: etag and
+// reports whether the server confirmed (304 Not Modified) that the cached
+// blob is still current
+func (d *Downloader) revalidate(url, etag string) bool {
+	if etag == "" {
+		return false
+	}
+
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("If-None-Match", etag)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusNotModified
+}
+
+// loadIndex reads the URL -> cacheIndexEntry map, returning an empty map if
+// it doesn't exist yet or fails to parse
+func (d *Downloader) loadIndex() map[string]cacheIndexEntry {
+	index := make(map[string]cacheIndexEntry)
+
+	data, err := os.ReadFile(d.indexPath())
+	if err != nil {
+		return index
+	}
+	json.Unmarshal(data, &index)
+	return index
+}
+
+// saveIndex persists the URL -> cacheIndexEntry map
+func (d *Downloader) saveIndex(index map[string]cacheIndexEntry) error {
+	if err := Mkdir(d.CacheDir); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding cache index: %v", err)
+	}
+
+	return os.WriteFile(d.indexPath(), data, 0644)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// HookEvent names the six lifecycle points a HookSet can fire commands on
+type HookEvent string
+
+const (
+	HookPreInstall  HookEvent = "pre_install"
+	HookPostInstall HookEvent = "post_install"
+	HookPreUpgrade  HookEvent = "pre_upgrade"
+	HookPostUpgrade HookEvent = "post_upgrade"
+	HookPreRemove   HookEvent = "pre_remove"
+	HookPostRemove  HookEvent = "post_remove"
+)
+
+// IsValidHookEvent reports whether event names one of the six lifecycle points
+func IsValidHookEvent(event string) bool {
+	switch HookEvent(event) {
+	case HookPreInstall, HookPostInstall, HookPreUpgrade, HookPostUpgrade, HookPreRemove, HookPostRemove:
+		return true
+	default:
+		return false
+	}
+}
+
+// commandsFor returns the shell commands registered for event
+func (h HookSet) commandsFor(event HookEvent) []string {
+	switch event {
+	case HookPreInstall:
+		return h.PreInstall
+	case HookPostInstall:
+		return h.PostInstall
+	case HookPreUpgrade:
+		return h.PreUpgrade
+	case HookPostUpgrade:
+		return h.PostUpgrade
+	case HookPreRemove:
+		return h.PreRemove
+	case HookPostRemove:
+		return h.PostRemove
+	default:
+		return nil
+	}
+}
+
+// ResolveHooks returns override if set, falling back to the global hook set
+// otherwise; override is a Release's own Hooks field, carried forward across
+// upgrades once set on a tool
+func ResolveHooks(config *ConfigManager, override *HookSet) HookSet {
+	if override != nil {
+		return *override
+	}
+	return config.GetHooks()
+}
+
+// HookEnv builds the IR_* environment variables passed to hook commands and
+// notification payloads
+func HookEnv(toolName, oldTag, newTag, assetPath string) map[string]string {
+	return map[string]string{
+		"IR_TOOL_NAME":  toolName,
+		"IR_OLD_TAG":    oldTag,
+		"IR_NEW_TAG":    newTag,
+		"IR_ASSET_PATH": assetPath,
+	}
+}
+
+// RunHooks runs every command registered for event, in order, via `sh -c`,
+// stopping and returning the first error. Each command's stdout/stderr is
+// streamed live when --debug is set, discarded entirely when --quiet is set,
+// and otherwise buffered and only shown if the command fails
+func RunHooks(hooks HookSet, event HookEvent, env map[string]string) error {
+	for _, command := range hooks.commandsFor(event) {
+		if err := runHookCommand(command, env); err != nil {
+			return fmt.Errorf("%s hook %q failed: %v", event, command, err)
+		}
+	}
+	return nil
+}
+
+func runHookCommand(command string, env map[string]string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	if quiet {
+		return cmd.Run()
+	}
+
+	if debug {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// NotifyEvent POSTs a small JSON payload describing event to the configured
+// webhook and/or Slack incoming-webhook URLs. Delivery failures are printed,
+// not returned, so a flaky notification endpoint never fails an
+// install/upgrade/remove
+func NotifyEvent(config *ConfigManager, event HookEvent, env map[string]string) {
+	notify := config.GetNotify()
+	if notify.WebhookURL == "" && notify.SlackURL == "" {
+		return
+	}
+
+	if notify.WebhookURL != "" {
+		payload, err := json.Marshal(map[string]string{
+			"event":   string(event),
+			"tool":    env["IR_TOOL_NAME"],
+			"old_tag": env["IR_OLD_TAG"],
+			"new_tag": env["IR_NEW_TAG"],
+		})
+		if err != nil {
+			PrintError(fmt.Sprintf("error encoding notification: %v", err))
+		} else {
+			postNotification(notify.WebhookURL, payload)
+		}
+	}
+
+	if notify.SlackURL != "" {
+		payload, err := json.Marshal(map[string]string{
+			"text": fmt.Sprintf("install-release: %s %s %s -> %s", event, env["IR_TOOL_NAME"], env["IR_OLD_TAG"], env["IR_NEW_TAG"]),
+		})
+		if err != nil {
+			PrintError(fmt.Sprintf("error encoding Slack notification: %v", err))
+		} else {
+			postNotification(notify.SlackURL, payload)
+		}
+	}
+}
+
+// postNotification sends payload as an HTTP POST, logging (not returning)
+// any failure
+func postNotification(url string, payload []byte) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		PrintError(fmt.Sprintf("error sending notification to %s: %v", url, err))
+		return
+	}
+	resp.Body.Close()
+}
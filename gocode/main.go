@@ -34,8 +34,15 @@ func main() {
 	rootCmd.AddCommand(configCmd())
 	rootCmd.AddCommand(stateCmd())
 	rootCmd.AddCommand(pullCmd())
+	rootCmd.AddCommand(pushCmd())
+	rootCmd.AddCommand(syncCmd())
 	rootCmd.AddCommand(holdCmd())
+	rootCmd.AddCommand(hooksCmd())
+	rootCmd.AddCommand(historyCmd())
+	rootCmd.AddCommand(rollbackCmd())
+	rootCmd.AddCommand(releaseCmd())
 	rootCmd.AddCommand(meCmd())
+	rootCmd.AddCommand(selfUpdateCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		// Error is already printed by Cobra
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestTool describes a single tool entry in a tools manifest
+type ManifestTool struct {
+	URL          string `yaml:"url" json:"url"`
+	Name         string `yaml:"name,omitempty" json:"name,omitempty"`
+	Tag          string `yaml:"tag,omitempty" json:"tag,omitempty"`
+	Hold         bool   `yaml:"hold,omitempty" json:"hold,omitempty"`
+	AssetPattern string `yaml:"asset_pattern,omitempty" json:"asset_pattern,omitempty"`
+	RenameTo     string `yaml:"rename_to,omitempty" json:"rename_to,omitempty"`
+	Path         string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// toolName returns the name this tool is installed and tracked in state
+// under: RenameTo takes priority over Name, which takes priority over a name
+// derived from URL
+func (t ManifestTool) toolName() string {
+	if t.RenameTo != "" {
+		return t.RenameTo
+	}
+	if t.Name != "" {
+		return t.Name
+	}
+	return toolNameFromURL(t.URL)
+}
+
+// Manifest is the declarative list of tools consumed by `ir pull` and
+// produced by `ir push`
+type Manifest struct {
+	Tools []ManifestTool `yaml:"tools" json:"tools"`
+}
+
+// FetchManifest loads a manifest from a local file path, or an http(s) URL
+// (including a raw GitHub/GitLab URL); JSON is used when source ends in
+// .json, YAML otherwise
+func FetchManifest(source string) (*Manifest, error) {
+	var data []byte
+	var err error
+
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = fetchManifestHTTP(source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading manifest: %v", err)
+	}
+
+	manifest := &Manifest{}
+	if strings.HasSuffix(strings.ToLower(source), ".json") {
+		if err := json.Unmarshal(data, manifest); err != nil {
+			return nil, fmt.Errorf("error parsing manifest as JSON: %v", err)
+		}
+		return manifest, nil
+	}
+
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing manifest as YAML: %v", err)
+	}
+	return manifest, nil
+}
+
+// fetchManifestHTTP downloads a manifest body from an http(s) URL
+func fetchManifestHTTP(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch failed with status: %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// ExportManifest builds a manifest from the current state, for `ir push`
+func ExportManifest(items map[string]*Release) *Manifest {
+	manifest := &Manifest{}
+	for key, release := range items {
+		if release.Uninstalled {
+			continue
+		}
+		irKey := ParseIrKey(key)
+		manifest.Tools = append(manifest.Tools, ManifestTool{
+			URL:  irKey.URL,
+			Name: irKey.Name,
+			Tag:  release.TagName,
+			Hold: release.HoldUpdate,
+		})
+	}
+	return manifest
+}
+
+// applyManifestTool installs or upgrades a single manifest entry, reusing the
+// same Install action as `get`. Tools already installed at the requested tag
+// are skipped unless override is set.
+func applyManifestTool(tool ManifestTool, config *ConfigManager, state *StateManager, override bool) error {
+	toolName := tool.toolName()
+
+	if existing, _, found := state.GetByName(toolName); found && !existing.Uninstalled && !override {
+		if tool.Tag == "" || existing.TagName == tool.Tag {
+			PrintInfo(fmt.Sprintf("Skipping %s: already installed at %s", toolName, existing.TagName))
+			return nil
+		}
+	}
+
+	install := &Install{
+		Config:  config,
+		State:   state,
+		URL:     tool.URL,
+		Name:    toolName,
+		TagName: tool.Tag,
+		Pick:    tool.AssetPattern,
+		Path:    tool.Path,
+	}
+	result, err := install.Run(nil, nil)
+	if err != nil {
+		return fmt.Errorf("error installing %s: %v", toolName, err)
+	}
+	result.Release.HoldUpdate = tool.Hold
+	state.SetByName(tool.URL, result.ToolName, result.Release)
+
+	PrintSuccess(fmt.Sprintf("Installed: %s (%s)", result.ToolName, result.Release.TagName))
+	return nil
+}
+
+// SyncAction describes what `ir sync` would do (or did) for one tool name
+type SyncAction struct {
+	Name    string
+	Action  string // "install", "upgrade", "remove", "up-to-date"
+	Tool    ManifestTool
+	Current string // installed tag, if any
+	Desired string // manifest tag, if any
+}
+
+// PlanSync diffs manifest against the current state: every manifest tool
+// not yet installed or installed at a different tag becomes an
+// "install"/"upgrade" action; every state entry whose name isn't in the
+// manifest becomes a "remove" action when prune is set. Used by both
+// `ir sync` and `ir sync --dry-run`, so the plan is identical either way.
+func PlanSync(manifest *Manifest, state *StateManager, prune bool) []SyncAction {
+	var plan []SyncAction
+
+	wanted := make(map[string]bool, len(manifest.Tools))
+	for _, tool := range manifest.Tools {
+		toolName := tool.toolName()
+		wanted[toolName] = true
+
+		existing, _, found := state.GetByName(toolName)
+		switch {
+		case !found || existing.Uninstalled:
+			plan = append(plan, SyncAction{Name: toolName, Action: "install", Tool: tool, Desired: tool.Tag})
+		case tool.Tag != "" && existing.TagName != tool.Tag:
+			plan = append(plan, SyncAction{Name: toolName, Action: "upgrade", Tool: tool, Current: existing.TagName, Desired: tool.Tag})
+		default:
+			plan = append(plan, SyncAction{Name: toolName, Action: "up-to-date", Tool: tool, Current: existing.TagName, Desired: tool.Tag})
+		}
+	}
+
+	if prune {
+		for key, release := range state.Items() {
+			if release.Uninstalled {
+				continue
+			}
+			name := ParseIrKey(key).Name
+			if !wanted[name] {
+				plan = append(plan, SyncAction{Name: name, Action: "remove", Current: release.TagName})
+			}
+		}
+	}
+
+	return plan
+}
+
+// ApplySync carries out a sync plan: installs/upgrades are applied through
+// applyManifestTool, removals through the Remove action (mirroring `ir
+// rm`). "up-to-date" entries are skipped. Returns the number of actions
+// that failed.
+func ApplySync(plan []SyncAction, config *ConfigManager, state *StateManager) int {
+	var failed int
+	for _, action := range plan {
+		var err error
+		switch action.Action {
+		case "install", "upgrade":
+			err = applyManifestTool(action.Tool, config, state, action.Action == "upgrade")
+		case "remove":
+			err = (&Remove{Config: config, State: state, Name: action.Name}).Run()
+		}
+		if err != nil {
+			PrintError(err.Error())
+			failed++
+		}
+	}
+	return failed
+}
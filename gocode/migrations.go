@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Migration transforms a raw, decoded config/state document from the schema
+// version it's keyed by (in configMigrations/stateMigrations) to the next
+// one, setting "schema_version" on its return value accordingly
+type Migration func(raw map[string]interface{}) (map[string]interface{}, error)
+
+// CurrentConfigSchemaVersion is the schema version ConfigManager.Load migrates up to
+const CurrentConfigSchemaVersion = 1
+
+// CurrentStateSchemaVersion is the schema version fileStateDriver.Load migrates up to
+const CurrentStateSchemaVersion = 1
+
+// configMigrations is keyed by the version each migration upgrades FROM
+var configMigrations = map[int]Migration{
+	0: migrateConfigV0toV1,
+}
+
+// stateMigrations is keyed by the version each migration upgrades FROM
+var stateMigrations = map[int]Migration{
+	0: migrateStateV0toV1,
+}
+
+// migrateConfigV0toV1 normalizes the pre-schema-version config file, which
+// ConfigManager.Load used to probe ad-hoc: either a flat ToolConfig object,
+// or wrapped as {"config": {...}} by an early Python-to-Go migration
+func migrateConfigV0toV1(raw map[string]interface{}) (map[string]interface{}, error) {
+	if wrapped, ok := raw["config"].(map[string]interface{}); ok {
+		raw = wrapped
+	}
+	raw["schema_version"] = float64(CurrentConfigSchemaVersion)
+	return raw, nil
+}
+
+// migrateStateV0toV1 wraps the pre-schema-version state file - a flat
+// "url#name" -> release map, matching the original Python State.save()
+// layout - into the versioned {"schema_version": 1, "releases": {...}} document
+func migrateStateV0toV1(raw map[string]interface{}) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"schema_version": float64(CurrentStateSchemaVersion),
+		"releases":       raw,
+	}, nil
+}
+
+// runMigrations reads raw["schema_version"] (defaulting to 0 for documents
+// that predate the field) and applies registered migrations sequentially
+// until reaching target, returning the migrated document and whether any
+// migration actually ran
+func runMigrations(raw map[string]interface{}, migrations map[int]Migration, target int) (map[string]interface{}, bool, error) {
+	migrated := false
+	for {
+		version := schemaVersionOf(raw)
+		if version >= target {
+			break
+		}
+		migrate, ok := migrations[version]
+		if !ok {
+			return nil, false, fmt.Errorf("no migration registered from schema version %d", version)
+		}
+		next, err := migrate(raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("migration from schema version %d failed: %v", version, err)
+		}
+		raw = next
+		migrated = true
+	}
+	return raw, migrated, nil
+}
+
+// schemaVersionOf reads the "schema_version" field of a decoded document,
+// defaulting to 0 when absent (every document written before this field existed)
+func schemaVersionOf(raw map[string]interface{}) int {
+	if v, ok := raw["schema_version"].(float64); ok {
+		return int(v)
+	}
+	return 0
+}
+
+// backupFile copies the file at path to "<path>.bak-<unix timestamp>" before
+// a migration overwrites it in place, so a bad migration can be recovered
+// from. It's a no-op if path doesn't exist yet.
+func backupFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error reading %s for backup: %v", path, err)
+	}
+
+	backupPath := fmt.Sprintf("%s.bak-%d", path, time.Now().Unix())
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing backup %s: %v", backupPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// AuxiliaryKind identifies what an AuxiliaryFile is, so InstallAuxiliaryFiles
+// knows which XDG location to copy it into
+type AuxiliaryKind string
+
+const (
+	AuxCompletionBash AuxiliaryKind = "bash-completion"
+	AuxCompletionZsh  AuxiliaryKind = "zsh-completion"
+	AuxCompletionFish AuxiliaryKind = "fish-completion"
+	AuxManPage        AuxiliaryKind = "man-page"
+)
+
+// AuxiliaryFile is one non-binary artifact found alongside a binary in an
+// extracted archive, e.g. a bash completion script or a man page
+type AuxiliaryFile struct {
+	SourcePath string
+	Kind       AuxiliaryKind
+	// DestName is the file's basename at the destination; for man pages
+	// ManDir holds the section directory (man1, man5, ...)
+	DestName string
+	ManDir   string
+}
+
+var manDirPattern = regexp.MustCompile(`^man[1-9]$`)
+
+// ScanAuxiliaryFiles walks extractedDir looking for well-known completion and
+// man page layouts (completions/{bash,zsh,fish}/*, share/man/man?/*). Returns
+// nil on Windows, where these XDG-style locations don't apply.
+func ScanAuxiliaryFiles(extractedDir string) ([]AuxiliaryFile, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+
+	var found []AuxiliaryFile
+
+	err := filepath.Walk(extractedDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		dir := filepath.Base(filepath.Dir(path))
+		name := info.Name()
+
+		switch dir {
+		case "bash":
+			if filepath.Base(filepath.Dir(filepath.Dir(path))) == "completions" {
+				found = append(found, AuxiliaryFile{SourcePath: path, Kind: AuxCompletionBash, DestName: name})
+			}
+		case "zsh":
+			if filepath.Base(filepath.Dir(filepath.Dir(path))) == "completions" {
+				found = append(found, AuxiliaryFile{SourcePath: path, Kind: AuxCompletionZsh, DestName: name})
+			}
+		case "fish":
+			if filepath.Base(filepath.Dir(filepath.Dir(path))) == "completions" {
+				found = append(found, AuxiliaryFile{SourcePath: path, Kind: AuxCompletionFish, DestName: name})
+			}
+		default:
+			if manDirPattern.MatchString(dir) && filepath.Base(filepath.Dir(filepath.Dir(path))) == "man" {
+				found = append(found, AuxiliaryFile{SourcePath: path, Kind: AuxManPage, DestName: name, ManDir: dir})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error scanning for auxiliary files: %v", err)
+	}
+
+	return found, nil
+}
+
+// destPath returns the XDG destination path for an auxiliary file
+func (f AuxiliaryFile) destPath() string {
+	dataHome := xdgDataHome()
+	switch f.Kind {
+	case AuxCompletionBash:
+		return filepath.Join(dataHome, "bash-completion", "completions", f.DestName)
+	case AuxCompletionZsh:
+		name := f.DestName
+		if !strings.HasPrefix(name, "_") {
+			name = "_" + name
+		}
+		return filepath.Join(dataHome, "zsh", "site-functions", name)
+	case AuxCompletionFish:
+		return filepath.Join(dataHome, "fish", "vendor_completions.d", f.DestName)
+	case AuxManPage:
+		return filepath.Join(dataHome, "man", f.ManDir, f.DestName)
+	default:
+		return ""
+	}
+}
+
+// InstallAuxiliaryFiles copies every scanned file to its XDG destination,
+// creating parent directories as needed, and returns every installed path so
+// the caller can persist it to state for Remove to clean up later
+func InstallAuxiliaryFiles(files []AuxiliaryFile) ([]string, error) {
+	var installed []string
+
+	for _, f := range files {
+		dest := f.destPath()
+		if dest == "" {
+			continue
+		}
+		if err := CopyFile(f.SourcePath, dest); err != nil {
+			return installed, fmt.Errorf("error installing %s: %v", f.SourcePath, err)
+		}
+		installed = append(installed, dest)
+	}
+
+	return installed, nil
+}
+
+// isOnPath reports whether dir is one of the entries in $PATH
+func isOnPath(dir string) bool {
+	pathEnv := os.Getenv("PATH")
+	sep := string(os.PathListSeparator)
+	for _, entry := range strings.Split(pathEnv, sep) {
+		if entry != "" && filepath.Clean(entry) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureBinOnPath checks whether installPath (where a binary was just
+// installed) is reachable via $PATH. If not, and ~/.local/bin is on $PATH, it
+// symlinks binPath into ~/.local/bin; otherwise it reports the exact
+// `export PATH=` line the user should add to their shell rc. Returns the
+// created shim's path, if any, so the caller can persist it for Remove.
+func EnsureBinOnPath(reporter Reporter, installPath, binPath, toolName string) string {
+	if isOnPath(installPath) {
+		return ""
+	}
+
+	localBin := filepath.Join(HOME, ".local", "bin")
+	if isOnPath(localBin) {
+		if err := Mkdir(localBin); err != nil {
+			reporter.Error(fmt.Sprintf("error creating %s: %v", localBin, err))
+			return ""
+		}
+
+		shimPath := filepath.Join(localBin, toolName+ExecutableExt())
+		os.Remove(shimPath) // replace a stale shim from a previous install, if any
+		if err := os.Symlink(binPath, shimPath); err != nil {
+			reporter.Error(fmt.Sprintf("error creating shim for %s: %v", toolName, err))
+			return ""
+		}
+		return shimPath
+	}
+
+	reporter.Info(fmt.Sprintf("%s is not on your PATH. Add this to your shell rc: export PATH=\"$PATH:%s\"", installPath, installPath))
+	return ""
+}
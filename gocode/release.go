@@ -0,0 +1,567 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ReleaseCreateOptions describes a release to publish via `ir release create`
+type ReleaseCreateOptions struct {
+	Tag        string
+	Target     string
+	Name       string
+	Notes      string
+	Draft      bool
+	Prerelease bool
+}
+
+// ReleaseEditOptions describes the fields `ir release edit` may change; a nil
+// pointer leaves that field untouched
+type ReleaseEditOptions struct {
+	Name       *string
+	Notes      *string
+	Draft      *bool
+	Prerelease *bool
+}
+
+// ReleasePublisher is the write-side counterpart to ReleaseProvider: it lets
+// install-release publish and manage releases on GitHub/GitLab, not just
+// resolve and download them
+type ReleasePublisher interface {
+	CreateRelease(opts ReleaseCreateOptions) (*Release, error)
+	ListReleases() ([]*Release, error)
+	GetRelease(tag string) (*Release, error)
+	EditRelease(tag string, opts ReleaseEditOptions) (*Release, error)
+	DeleteRelease(tag string) error
+	UploadAsset(tag, path, label string) (*ReleaseAssets, error)
+}
+
+// GetReleasePublisher creates the appropriate ReleasePublisher based on URL,
+// mirroring GetRepoInfo's host detection for GitHub/GitHub Enterprise and
+// GitLab/self-hosted GitLab
+func GetReleasePublisher(repoURL string, cfg *ToolConfig) (ReleasePublisher, error) {
+	u, err := url.Parse(strings.TrimSuffix(repoURL, "/"))
+	if err != nil || u.Host == "" {
+		return nil, &UnsupportedRepositoryError{Message: "Invalid repository URL: " + repoURL}
+	}
+
+	switch {
+	case u.Host == "github.com" || contains(cfg.GithubEnterpriseHosts, u.Host):
+		return NewGitHubInfo(repoURL, resolveHostToken(cfg, u.Host, cfg.Token))
+	case u.Host == "gitlab.com" || contains(cfg.GitlabHosts, u.Host):
+		return NewGitLabInfo(repoURL, resolveHostToken(cfg, u.Host, cfg.GitlabToken))
+	}
+
+	return nil, &UnsupportedRepositoryError{Message: "Unsupported repository type"}
+}
+
+// DetectRepoURL resolves the repository to publish against: repoFlag (as
+// passed to --repo, either "owner/name" or a full URL) takes precedence,
+// otherwise it's read from the current directory's git "origin" remote
+func DetectRepoURL(repoFlag string) (string, error) {
+	if repoFlag != "" {
+		if strings.Contains(repoFlag, "://") {
+			return repoFlag, nil
+		}
+		if strings.Count(repoFlag, "/") == 1 {
+			return "https://github.com/" + repoFlag, nil
+		}
+		return "", fmt.Errorf("--repo must be \"owner/name\" or a full repository URL, got %q", repoFlag)
+	}
+
+	remote, err := RunCommandWithOutput("git", "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("could not detect repository from git remote \"origin\" (%v), pass --repo owner/name", err)
+	}
+
+	return normalizeGitRemoteURL(strings.TrimSpace(remote))
+}
+
+// normalizeGitRemoteURL converts a git remote URL (https://host/owner/repo.git
+// or git@host:owner/repo.git) into the https://host/owner/repo form the rest
+// of install-release expects
+func normalizeGitRemoteURL(remote string) (string, error) {
+	remote = strings.TrimSuffix(remote, ".git")
+
+	if strings.HasPrefix(remote, "git@") {
+		rest := strings.TrimPrefix(remote, "git@")
+		host, path, ok := strings.Cut(rest, ":")
+		if !ok {
+			return "", fmt.Errorf("unrecognized git remote URL: %s", remote)
+		}
+		return fmt.Sprintf("https://%s/%s", host, path), nil
+	}
+
+	if strings.HasPrefix(remote, "ssh://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", fmt.Errorf("unrecognized git remote URL: %s", remote)
+		}
+		return fmt.Sprintf("https://%s%s", u.Host, u.Path), nil
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("unrecognized git remote URL: %s", remote)
+	}
+	return fmt.Sprintf("https://%s%s", u.Host, u.Path), nil
+}
+
+// ParseReleaseAsset splits a `--asset path[#label]` argument into its path
+// and optional display label
+func ParseReleaseAsset(arg string) (path, label string) {
+	path, label, _ = strings.Cut(arg, "#")
+	return path, label
+}
+
+// ResolveNotes returns notes, or the contents of notesFile if set, or
+// stdin if notesFile is "-"
+func ResolveNotes(notes, notesFile string) (string, error) {
+	if notesFile == "" {
+		return notes, nil
+	}
+	if notesFile == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("error reading notes from stdin: %v", err)
+		}
+		return string(data), nil
+	}
+	data, err := os.ReadFile(notesFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading notes file: %v", err)
+	}
+	return string(data), nil
+}
+
+// --- GitHub ---
+
+type ghReleasePayload struct {
+	TagName         string `json:"tag_name"`
+	TargetCommitish string `json:"target_commitish,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Body            string `json:"body,omitempty"`
+	Draft           bool   `json:"draft"`
+	Prerelease      bool   `json:"prerelease"`
+}
+
+// CreateRelease creates a new GitHub release via POST {api}/releases
+func (gh *GitHubInfo) CreateRelease(opts ReleaseCreateOptions) (*Release, error) {
+	payload := ghReleasePayload{
+		TagName:         opts.Tag,
+		TargetCommitish: opts.Target,
+		Name:            opts.Name,
+		Body:            opts.Notes,
+		Draft:           opts.Draft,
+		Prerelease:      opts.Prerelease,
+	}
+	data, err := gh.writeReq("POST", gh.api+"/releases", payload)
+	if err != nil {
+		return nil, err
+	}
+	return gh.decodeRelease(data)
+}
+
+// ListReleases lists every release via GET {api}/releases
+func (gh *GitHubInfo) ListReleases() ([]*Release, error) {
+	data, err := gh.req(gh.api + "/releases")
+	if err != nil {
+		return nil, err
+	}
+	var releases []*Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("error unmarshaling releases: %v", err)
+	}
+	for _, release := range releases {
+		release.URL = fmt.Sprintf("https://%s/%s/%s", gh.host, gh.owner, gh.repoName)
+	}
+	return releases, nil
+}
+
+// GetRelease fetches a single release by tag via GET {api}/releases/tags/{tag},
+// falling back to scraping the release page when the API rate-limits us
+func (gh *GitHubInfo) GetRelease(tag string) (*Release, error) {
+	data, err := gh.req(fmt.Sprintf("%s/releases/tags/%s", gh.api, tag))
+	if err != nil {
+		if isRateLimited(err) {
+			release, scrapeErr := gh.scrapeRelease(tag)
+			if scrapeErr != nil {
+				return nil, fmt.Errorf("%v (scrape fallback also failed: %v)", err, scrapeErr)
+			}
+			return release, nil
+		}
+		return nil, err
+	}
+	return gh.decodeRelease(data)
+}
+
+// EditRelease updates the given fields of an existing release via PATCH {api}/releases/{id}
+func (gh *GitHubInfo) EditRelease(tag string, opts ReleaseEditOptions) (*Release, error) {
+	existing, err := gh.GetRelease(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := ghReleasePayload{
+		TagName:    tag,
+		Name:       existing.Name,
+		Body:       existing.Body,
+		Draft:      existing.Draft,
+		Prerelease: existing.Prerelease,
+	}
+	if opts.Name != nil {
+		payload.Name = *opts.Name
+	}
+	if opts.Notes != nil {
+		payload.Body = *opts.Notes
+	}
+	if opts.Draft != nil {
+		payload.Draft = *opts.Draft
+	}
+	if opts.Prerelease != nil {
+		payload.Prerelease = *opts.Prerelease
+	}
+
+	data, err := gh.writeReq("PATCH", fmt.Sprintf("%s/releases/%d", gh.api, existing.ID), payload)
+	if err != nil {
+		return nil, err
+	}
+	return gh.decodeRelease(data)
+}
+
+// DeleteRelease removes a release (but not its tag) via DELETE {api}/releases/{id}
+func (gh *GitHubInfo) DeleteRelease(tag string) error {
+	existing, err := gh.GetRelease(tag)
+	if err != nil {
+		return err
+	}
+	_, err = gh.writeReq("DELETE", fmt.Sprintf("%s/releases/%d", gh.api, existing.ID), nil)
+	return err
+}
+
+// UploadAsset uploads path as a release asset via the GitHub uploads endpoint
+func (gh *GitHubInfo) UploadAsset(tag, path, label string) (*ReleaseAssets, error) {
+	existing, err := gh.GetRelease(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening asset %s: %v", path, err)
+	}
+	defer file.Close()
+
+	name := filepath.Base(path)
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	uploadURL := fmt.Sprintf("%s/%d/assets?name=%s", gh.uploadURL, existing.ID, url.QueryEscape(name))
+	if label != "" {
+		uploadURL += "&label=" + url.QueryEscape(label)
+	}
+
+	req, err := http.NewRequest("POST", uploadURL, file)
+	if err != nil {
+		return nil, fmt.Errorf("error creating upload request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if gh.token != "" {
+		req.Header.Set("Authorization", "token "+gh.token)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error uploading asset: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading upload response: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return nil, &ApiError{Message: fmt.Sprintf("asset upload failed with status %d: %s", resp.StatusCode, string(body))}
+	}
+
+	var asset ReleaseAssets
+	if err := json.Unmarshal(body, &asset); err != nil {
+		return nil, fmt.Errorf("error unmarshaling uploaded asset: %v", err)
+	}
+	return &asset, nil
+}
+
+// decodeRelease unmarshals a GitHub release JSON body and fills in the
+// repository URL field, which the API response doesn't carry
+func (gh *GitHubInfo) decodeRelease(data []byte) (*Release, error) {
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("error unmarshaling release: %v", err)
+	}
+	release.URL = fmt.Sprintf("https://%s/%s/%s", gh.host, gh.owner, gh.repoName)
+	return &release, nil
+}
+
+// writeReq makes a non-GET request to the GitHub API with a JSON body
+// (body may be nil, e.g. for DELETE)
+func (gh *GitHubInfo) writeReq(method, apiURL string, body interface{}) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, apiURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	for k, v := range gh.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if gh.token != "" {
+		req.Header.Set("Authorization", "token "+gh.token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &ApiError{Message: fmt.Sprintf("GitHub API request failed with status %d: %s", resp.StatusCode, string(respBody))}
+	}
+
+	return respBody, nil
+}
+
+// --- GitLab ---
+
+type glReleasePayload struct {
+	TagName     string `json:"tag_name"`
+	Ref         string `json:"ref,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	ReleasedAt  string `json:"released_at,omitempty"`
+}
+
+// CreateRelease creates a new GitLab release via POST {api}/releases. GitLab
+// releases have no separate draft/prerelease flags, so opts.Draft/Prerelease
+// are accepted for interface parity with GitHub but have no effect here.
+func (gl *GitLabInfo) CreateRelease(opts ReleaseCreateOptions) (*Release, error) {
+	payload := glReleasePayload{
+		TagName:     opts.Tag,
+		Ref:         opts.Target,
+		Name:        opts.Name,
+		Description: opts.Notes,
+	}
+	data, err := gl.writeReq("POST", gl.api+"/releases", payload)
+	if err != nil {
+		return nil, err
+	}
+	return gl.decodeRelease(data)
+}
+
+// ListReleases lists every release via GET {api}/releases
+func (gl *GitLabInfo) ListReleases() ([]*Release, error) {
+	data, err := gl.req(gl.api + "/releases")
+	if err != nil {
+		return nil, err
+	}
+	var raw []glRelease
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("error unmarshaling releases: %v", err)
+	}
+	releases := make([]*Release, len(raw))
+	for i, r := range raw {
+		releases[i] = gl.toRelease(r)
+	}
+	return releases, nil
+}
+
+// GetRelease fetches a single release by tag via GET {api}/releases/{tag}
+func (gl *GitLabInfo) GetRelease(tag string) (*Release, error) {
+	data, err := gl.req(fmt.Sprintf("%s/releases/%s", gl.api, tag))
+	if err != nil {
+		return nil, err
+	}
+	return gl.decodeRelease(data)
+}
+
+// EditRelease updates the given fields of an existing release via PUT {api}/releases/{tag}
+func (gl *GitLabInfo) EditRelease(tag string, opts ReleaseEditOptions) (*Release, error) {
+	existing, err := gl.GetRelease(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := glReleasePayload{
+		TagName:     tag,
+		Name:        existing.Name,
+		Description: existing.Body,
+	}
+	if opts.Name != nil {
+		payload.Name = *opts.Name
+	}
+	if opts.Notes != nil {
+		payload.Description = *opts.Notes
+	}
+
+	data, err := gl.writeReq("PUT", fmt.Sprintf("%s/releases/%s", gl.api, tag), payload)
+	if err != nil {
+		return nil, err
+	}
+	return gl.decodeRelease(data)
+}
+
+// DeleteRelease removes a release (but not its tag) via DELETE {api}/releases/{tag}
+func (gl *GitLabInfo) DeleteRelease(tag string) error {
+	_, err := gl.writeReq("DELETE", fmt.Sprintf("%s/releases/%s", gl.api, tag), nil)
+	return err
+}
+
+// glReleaseLinkPayload is the body of GitLab's release-links API
+type glReleaseLinkPayload struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	LinkType string `json:"link_type,omitempty"`
+}
+
+// UploadAsset registers path as a release link via GitLab's release-links
+// API. Unlike GitHub, GitLab's release-links API only records a URL to an
+// already-hosted file rather than accepting a raw upload, so path must be a
+// reachable URL (e.g. one uploaded to the project's generic package registry).
+func (gl *GitLabInfo) UploadAsset(tag, path, label string) (*ReleaseAssets, error) {
+	if !strings.Contains(path, "://") {
+		return nil, fmt.Errorf("GitLab release assets must be a URL already hosted somewhere (e.g. the project's package registry), got %q", path)
+	}
+
+	name := label
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	payload := glReleaseLinkPayload{Name: name, URL: path}
+	data, err := gl.writeReq("POST", fmt.Sprintf("%s/releases/%s/assets/links", gl.api, tag), payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var link struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &link); err != nil {
+		return nil, fmt.Errorf("error unmarshaling release link: %v", err)
+	}
+	return &ReleaseAssets{Name: name, BrowserDownloadURL: link.URL}, nil
+}
+
+// glRelease mirrors GitLab's release JSON shape, which names the notes field
+// "description" and nests assets under "assets.links" instead of a flat list
+type glRelease struct {
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ReleasedAt  string `json:"released_at"`
+	Assets      struct {
+		Links []struct {
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+func (gl *GitLabInfo) toRelease(r glRelease) *Release {
+	release := &Release{
+		URL:         fmt.Sprintf("https://%s/%s", gl.host, gl.projectPath),
+		Name:        r.Name,
+		TagName:     r.TagName,
+		Body:        r.Description,
+		PublishedAt: r.ReleasedAt,
+	}
+	for _, link := range r.Assets.Links {
+		downloadURL := link.DirectAssetURL
+		if downloadURL == "" {
+			downloadURL = link.URL
+		}
+		release.Assets = append(release.Assets, ReleaseAssets{
+			Name:               link.Name,
+			BrowserDownloadURL: downloadURL,
+		})
+	}
+	return release
+}
+
+func (gl *GitLabInfo) decodeRelease(data []byte) (*Release, error) {
+	var r glRelease
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("error unmarshaling release: %v", err)
+	}
+	return gl.toRelease(r), nil
+}
+
+// writeReq makes a non-GET request to the GitLab API with a JSON body
+// (body may be nil, e.g. for DELETE)
+func (gl *GitLabInfo) writeReq(method, apiURL string, body interface{}) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, apiURL, reader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	for k, v := range gl.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if gl.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", gl.token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &ApiError{Message: fmt.Sprintf("GitLab API request failed with status %d: %s", resp.StatusCode, string(respBody))}
+	}
+
+	return respBody, nil
+}
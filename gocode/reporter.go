@@ -0,0 +1,36 @@
+package main
+
+// Reporter receives progress events from action Run methods, decoupling the
+// lipgloss/stdout presentation from the install/upgrade/remove/hold logic so
+// the same logic can run under the CLI, a future JSON output mode, or a test
+// harness
+type Reporter interface {
+	Info(message string)
+	Success(message string)
+	Error(message string)
+	Section(title string)
+	Table(rows []map[string]string, headers []string, colorFuncs []func(string) string)
+}
+
+// StdoutReporter is the default Reporter, backed by the existing
+// lipgloss/stdout Print* helpers
+type StdoutReporter struct{}
+
+func (StdoutReporter) Info(message string)    { PrintInfo(message) }
+func (StdoutReporter) Success(message string) { PrintSuccess(message) }
+func (StdoutReporter) Error(message string)   { PrintError(message) }
+func (StdoutReporter) Section(title string)   { PrintSection(title) }
+func (StdoutReporter) Table(rows []map[string]string, headers []string, colorFuncs []func(string) string) {
+	PrintTable(rows, headers, colorFuncs)
+}
+
+// SilentReporter discards all output, useful for tests and library callers
+// that only care about the returned result
+type SilentReporter struct{}
+
+func (SilentReporter) Info(string)    {}
+func (SilentReporter) Success(string) {}
+func (SilentReporter) Error(string)   {}
+func (SilentReporter) Section(string) {}
+func (SilentReporter) Table([]map[string]string, []string, []func(string) string) {
+}
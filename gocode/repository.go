@@ -5,8 +5,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
 	"strings"
+	"text/template"
 	"time"
+
+	"golang.org/x/net/html"
 )
 
 // RepositoryError represents repository operation errors
@@ -36,50 +42,103 @@ func (e ApiError) Error() string {
 	return e.Message
 }
 
-// RepoInfo is the interface for repository information
-type RepoInfo interface {
+// ReleaseProvider is the interface for resolving and downloading releases
+// from a forge/mirror. GetRepoInfo dispatches to the concrete implementation
+// for a given repository URL (GitHub, GitLab, Gitea/Forgejo, or a generic
+// URL template).
+type ReleaseProvider interface {
 	Repository() (*RepositoryInfo, error)
-	Release(tagName string, preRelease bool) ([]*Release, error)
+	// LatestRelease fetches the newest release, excluding prereleases unless
+	// preRelease is set
+	LatestRelease(preRelease bool) (*Release, error)
+	// ListReleases lists every release, newest first
+	ListReleases() ([]*Release, error)
+	// GetRelease fetches a single release by tag
+	GetRelease(tagName string) (*Release, error)
+	// DownloadAsset saves asset directly to destPath
+	DownloadAsset(asset *ReleaseAssets, destPath string) error
 }
 
 // GitHubInfo handles GitHub repository operations
 type GitHubInfo struct {
-	owner    string
-	repoName string
-	api      string
-	token    string
-	headers  map[string]string
+	host      string
+	owner     string
+	repoName  string
+	api       string
+	uploadURL string
+	token     string
+	headers   map[string]string
 }
 
-// NewGitHubInfo creates a new GitHub repository handler
-func NewGitHubInfo(repoURL, token string) (*GitHubInfo, error) {
-	if !strings.Contains(repoURL, "github.com") {
-		return nil, &UnsupportedRepositoryError{Message: "Repository URL must contain 'github.com'"}
+// isRateLimited reports whether an error from req represents a rate-limit
+// or abuse-detection response that the HTML scrape fallback can recover from
+func isRateLimited(err error) bool {
+	apiErr, ok := err.(*ApiError)
+	if !ok {
+		return false
 	}
+	return strings.Contains(apiErr.Message, "403") || strings.Contains(apiErr.Message, "429")
+}
 
-	// Remove trailing slash
-	repoURL = strings.TrimSuffix(repoURL, "/")
+// filterPrerelease drops pre-release entries from a release list
+func filterPrerelease(releases []*Release) []*Release {
+	filtered := make([]*Release, 0)
+	for _, release := range releases {
+		if !release.Prerelease {
+			filtered = append(filtered, release)
+		}
+	}
+	return filtered
+}
 
-	// Parse repository information from URL
-	parts := strings.Split(repoURL, "/")
-	if len(parts) < 5 {
-		return nil, &UnsupportedRepositoryError{Message: "Invalid GitHub repository URL"}
+// NewGitHubInfo creates a new GitHub repository handler. repoURL may point at
+// public github.com or at a GitHub Enterprise host (e.g. https://ghe.mycorp.com/owner/repo) -
+// the API root is derived from the URL's host.
+func NewGitHubInfo(repoURL, token string) (*GitHubInfo, error) {
+	host, owner, repoName, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
 	}
 
-	owner := parts[len(parts)-2]
-	repoName := parts[len(parts)-1]
+	var api, uploadURL string
+	if host == "github.com" {
+		api = fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repoName)
+		uploadURL = fmt.Sprintf("https://uploads.github.com/repos/%s/%s", owner, repoName)
+	} else {
+		// GitHub Enterprise Server exposes the same API shape under /api/v3
+		api = fmt.Sprintf("https://%s/api/v3/repos/%s/%s", host, owner, repoName)
+		uploadURL = fmt.Sprintf("https://%s/api/uploads/repos/%s/%s", host, owner, repoName)
+	}
 
 	return &GitHubInfo{
-		owner:    owner,
-		repoName: repoName,
-		api:      fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repoName),
-		token:    token,
+		host:      host,
+		owner:     owner,
+		repoName:  repoName,
+		api:       api,
+		uploadURL: uploadURL,
+		token:     token,
 		headers: map[string]string{
 			"Accept": "application/vnd.github.v3+json",
 		},
 	}, nil
 }
 
+// parseRepoURL extracts the host, owner and repository name from a
+// repository URL, e.g. https://github.com/owner/repo -> ("github.com", "owner", "repo")
+func parseRepoURL(repoURL string) (host, owner, repoName string, err error) {
+	u, parseErr := url.Parse(strings.TrimSuffix(repoURL, "/"))
+	if parseErr != nil || u.Host == "" {
+		return "", "", "", &UnsupportedRepositoryError{Message: "Invalid repository URL: " + repoURL}
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", &UnsupportedRepositoryError{Message: "Invalid repository URL: " + repoURL}
+	}
+
+	return u.Host, parts[0], parts[1], nil
+}
+
 // Repository gets repository information
 func (gh *GitHubInfo) Repository() (*RepositoryInfo, error) {
 	data, err := gh.req(gh.api)
@@ -95,55 +154,37 @@ func (gh *GitHubInfo) Repository() (*RepositoryInfo, error) {
 	return &repoInfo, nil
 }
 
-// Release gets release information
-func (gh *GitHubInfo) Release(tagName string, preRelease bool) ([]*Release, error) {
-	var api string
-	var isLatest bool
-	if tagName != "" {
-		api = fmt.Sprintf("%s/releases/tags/%s", gh.api, tagName)
-	} else {
-		api = fmt.Sprintf("%s/releases/latest", gh.api)
-		isLatest = true
-	}
-
-	data, err := gh.req(api)
+// LatestRelease gets the newest release via GET {api}/releases/latest,
+// falling back to scraping the release page when the API rate-limits us
+func (gh *GitHubInfo) LatestRelease(preRelease bool) (*Release, error) {
+	data, err := gh.req(gh.api + "/releases/latest")
 	if err != nil {
+		if isRateLimited(err) {
+			release, scrapeErr := gh.scrapeRelease("")
+			if scrapeErr != nil {
+				return nil, fmt.Errorf("%v (scrape fallback also failed: %v)", err, scrapeErr)
+			}
+			if !preRelease && release.Prerelease {
+				return nil, fmt.Errorf("no releases found")
+			}
+			return release, nil
+		}
 		return nil, err
 	}
 
-	var releases []*Release
-	if tagName != "" || isLatest {
-		// Single release (tag or latest)
-		var release Release
-		if err := json.Unmarshal(data, &release); err != nil {
-			return nil, fmt.Errorf("error unmarshaling release: %v", err)
-		}
-		// Set the correct repository URL instead of API URL
-		release.URL = fmt.Sprintf("https://github.com/%s/%s", gh.owner, gh.repoName)
-		releases = append(releases, &release)
-	} else {
-		// Multiple releases
-		if err := json.Unmarshal(data, &releases); err != nil {
-			return nil, fmt.Errorf("error unmarshaling releases: %v", err)
-		}
-		// Set the correct repository URL for all releases
-		for i := range releases {
-			releases[i].URL = fmt.Sprintf("https://github.com/%s/%s", gh.owner, gh.repoName)
-		}
+	release, err := gh.decodeRelease(data)
+	if err != nil {
+		return nil, err
 	}
-
-	// Filter by pre-release flag if needed
-	if !preRelease {
-		filtered := make([]*Release, 0)
-		for _, release := range releases {
-			if !release.Prerelease {
-				filtered = append(filtered, release)
-			}
-		}
-		releases = filtered
+	if !preRelease && release.Prerelease {
+		return nil, fmt.Errorf("no releases found")
 	}
+	return release, nil
+}
 
-	return releases, nil
+// DownloadAsset saves asset directly to destPath
+func (gh *GitHubInfo) DownloadAsset(asset *ReleaseAssets, destPath string) error {
+	return Download(asset.BrowserDownloadURL, destPath)
 }
 
 // req makes a request to the GitHub API
@@ -194,38 +235,188 @@ func (gh *GitHubInfo) req(url string) ([]byte, error) {
 	return body, nil
 }
 
+// scrapeRelease fetches a release page from github.com directly and parses the
+// rendered HTML, bypassing the unauthenticated api.github.com rate limit
+func (gh *GitHubInfo) scrapeRelease(tagName string) (*Release, error) {
+	var pageURL string
+	if tagName != "" {
+		pageURL = fmt.Sprintf("https://%s/%s/%s/releases/tag/%s", gh.host, gh.owner, gh.repoName, tagName)
+	} else {
+		pageURL = fmt.Sprintf("https://%s/%s/%s/releases/latest", gh.host, gh.owner, gh.repoName)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release page request failed with status: %d", resp.StatusCode)
+	}
+
+	doc, err := html.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing release page: %v", err)
+	}
+
+	resolvedTag := tagName
+	if resolvedTag == "" {
+		resolvedTag = tagNameFromURL(resp.Request.URL.String())
+	}
+
+	release := &Release{
+		URL:     fmt.Sprintf("https://%s/%s/%s", gh.host, gh.owner, gh.repoName),
+		TagName: resolvedTag,
+		Name:    resolvedTag,
+		Assets:  scrapeReleaseAssets(doc, gh.host, gh.owner, gh.repoName, resolvedTag),
+	}
+
+	if len(release.Assets) == 0 {
+		return nil, fmt.Errorf("no downloadable assets found on release page")
+	}
+
+	return release, nil
+}
+
+// tagNameFromURL extracts the tag name from a resolved releases/tag/{tag} URL,
+// used when /releases/latest redirects to the concrete tag page
+func tagNameFromURL(url string) string {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	return parts[len(parts)-1]
+}
+
+// scrapeReleaseAssets walks the parsed release page looking for anchors that
+// link to a downloadable asset for the given tag
+func scrapeReleaseAssets(n *html.Node, host, owner, repoName, tagName string) []ReleaseAssets {
+	var assets []ReleaseAssets
+	downloadPrefix := fmt.Sprintf("/%s/%s/releases/download/%s/", owner, repoName, tagName)
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "a" {
+			href := attrValue(node, "href")
+			if strings.Contains(href, downloadPrefix) {
+				name := strings.TrimSpace(textContent(node))
+				if name == "" {
+					parts := strings.Split(strings.TrimSuffix(href, "/"), "/")
+					name = parts[len(parts)-1]
+				}
+
+				assets = append(assets, ReleaseAssets{
+					Name:               name,
+					BrowserDownloadURL: "https://" + host + href,
+					Size:               sizeFromSibling(node),
+				})
+			}
+		}
+
+		for c := node.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return assets
+}
+
+// attrValue returns the value of the named attribute on a node, or "" if absent
+func attrValue(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// textContent concatenates all text node descendants of n
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textContent(c))
+	}
+	return sb.String()
+}
+
+// sizeFromSibling looks for a sibling <small> element holding a human readable
+// size (e.g. "12.3 MB") and converts it to bytes; returns 0 if not found
+func sizeFromSibling(n *html.Node) int {
+	for sib := n.NextSibling; sib != nil; sib = sib.NextSibling {
+		if sib.Type == html.ElementNode && sib.Data == "small" {
+			return parseHumanSize(textContent(sib))
+		}
+	}
+	return 0
+}
+
+// parseHumanSize converts a string like "12.3 MB" or "512 KB" into bytes
+func parseHumanSize(s string) int {
+	fields := strings.Fields(strings.TrimSpace(s))
+	if len(fields) != 2 {
+		return 0
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+
+	switch strings.ToUpper(fields[1]) {
+	case "GB":
+		return int(value * 1024 * 1024 * 1024)
+	case "MB":
+		return int(value * 1024 * 1024)
+	case "KB":
+		return int(value * 1024)
+	case "B":
+		return int(value)
+	default:
+		return 0
+	}
+}
+
 // GitLabInfo handles GitLab repository operations
 type GitLabInfo struct {
-	owner    string
-	repoName string
-	api      string
-	token    string
-	headers  map[string]string
+	host        string
+	projectPath string
+	api         string
+	token       string
+	headers     map[string]string
 }
 
-// NewGitLabInfo creates a new GitLab repository handler
+// NewGitLabInfo creates a new GitLab repository handler. repoURL may point at
+// public gitlab.com or at a self-hosted instance (e.g. https://gitlab.mycorp.com/group/subgroup/repo) -
+// the API root is derived from the URL's host, and nested groups are supported
+// by URL-encoding the full project path rather than just the last two segments.
 func NewGitLabInfo(repoURL, token string) (*GitLabInfo, error) {
-	if !strings.Contains(repoURL, "gitlab.com") {
-		return nil, &UnsupportedRepositoryError{Message: "Repository URL must contain 'gitlab.com'"}
+	u, err := url.Parse(strings.TrimSuffix(repoURL, "/"))
+	if err != nil || u.Host == "" {
+		return nil, &UnsupportedRepositoryError{Message: "Invalid GitLab repository URL: " + repoURL}
 	}
 
-	// Remove trailing slash
-	repoURL = strings.TrimSuffix(repoURL, "/")
-
-	// Parse repository information from URL
-	parts := strings.Split(repoURL, "/")
-	if len(parts) < 5 {
-		return nil, &UnsupportedRepositoryError{Message: "Invalid GitLab repository URL"}
+	projectPath := strings.Trim(u.Path, "/")
+	if !strings.Contains(projectPath, "/") {
+		return nil, &UnsupportedRepositoryError{Message: "Invalid GitLab repository URL: " + repoURL}
 	}
 
-	owner := parts[len(parts)-2]
-	repoName := parts[len(parts)-1]
+	var api string
+	if u.Host == "gitlab.com" {
+		api = fmt.Sprintf("https://gitlab.com/api/v4/projects/%s", url.QueryEscape(projectPath))
+	} else {
+		api = fmt.Sprintf("https://%s/api/v4/projects/%s", u.Host, url.QueryEscape(projectPath))
+	}
 
 	return &GitLabInfo{
-		owner:    owner,
-		repoName: repoName,
-		api:      fmt.Sprintf("https://gitlab.com/api/v4/projects/%s%%2F%s", owner, repoName),
-		token:    token,
+		host:        u.Host,
+		projectPath: projectPath,
+		api:         api,
+		token:       token,
 		headers: map[string]string{
 			"Accept": "application/json",
 		},
@@ -247,53 +438,26 @@ func (gl *GitLabInfo) Repository() (*RepositoryInfo, error) {
 	return &repoInfo, nil
 }
 
-// Release gets release information
-func (gl *GitLabInfo) Release(tagName string, preRelease bool) ([]*Release, error) {
-	var api string
-	if tagName != "" {
-		api = fmt.Sprintf("%s/releases/%s", gl.api, tagName)
-	} else {
-		api = fmt.Sprintf("%s/releases", gl.api)
-	}
-
-	data, err := gl.req(api)
+// LatestRelease gets the newest release. GitLab has no dedicated "latest"
+// endpoint, so this takes the first entry from ListReleases, which GitLab
+// returns ordered newest-released-first.
+func (gl *GitLabInfo) LatestRelease(preRelease bool) (*Release, error) {
+	releases, err := gl.ListReleases()
 	if err != nil {
 		return nil, err
 	}
-
-	var releases []*Release
-	if tagName != "" {
-		// Single release
-		var release Release
-		if err := json.Unmarshal(data, &release); err != nil {
-			return nil, fmt.Errorf("error unmarshaling release: %v", err)
-		}
-		// Set the correct repository URL instead of API URL
-		release.URL = fmt.Sprintf("https://gitlab.com/%s/%s", gl.owner, gl.repoName)
-		releases = append(releases, &release)
-	} else {
-		// Multiple releases
-		if err := json.Unmarshal(data, &releases); err != nil {
-			return nil, fmt.Errorf("error unmarshaling releases: %v", err)
-		}
-		// Set the correct repository URL for all releases
-		for i := range releases {
-			releases[i].URL = fmt.Sprintf("https://gitlab.com/%s/%s", gl.owner, gl.repoName)
-		}
-	}
-
-	// Filter by pre-release flag if needed
 	if !preRelease {
-		filtered := make([]*Release, 0)
-		for _, release := range releases {
-			if !release.Prerelease {
-				filtered = append(filtered, release)
-			}
-		}
-		releases = filtered
+		releases = filterPrerelease(releases)
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no releases found")
 	}
+	return releases[0], nil
+}
 
-	return releases, nil
+// DownloadAsset saves asset directly to destPath
+func (gl *GitLabInfo) DownloadAsset(asset *ReleaseAssets, destPath string) error {
+	return Download(asset.BrowserDownloadURL, destPath)
 }
 
 // req makes a request to the GitLab API
@@ -333,13 +497,268 @@ func (gl *GitLabInfo) req(url string) ([]byte, error) {
 	return body, nil
 }
 
+// GiteaInfo handles Gitea/Forgejo repository operations (also covers
+// Codeberg, a public Forgejo instance). Gitea's release API mirrors GitHub's
+// response shape closely enough to reuse Release/ReleaseAssets as-is.
+type GiteaInfo struct {
+	host     string
+	owner    string
+	repoName string
+	api      string
+	token    string
+	headers  map[string]string
+}
+
+// NewGiteaInfo creates a new Gitea/Forgejo repository handler, e.g. for
+// https://codeberg.org/owner/repo or a self-hosted https://git.mycorp.com/owner/repo
+func NewGiteaInfo(repoURL, token string) (*GiteaInfo, error) {
+	host, owner, repoName, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GiteaInfo{
+		host:     host,
+		owner:    owner,
+		repoName: repoName,
+		api:      fmt.Sprintf("https://%s/api/v1/repos/%s/%s", host, owner, repoName),
+		token:    token,
+		headers: map[string]string{
+			"Accept": "application/json",
+		},
+	}, nil
+}
+
+// Repository gets repository information
+func (gt *GiteaInfo) Repository() (*RepositoryInfo, error) {
+	data, err := gt.req(gt.api)
+	if err != nil {
+		return nil, err
+	}
+
+	var repoInfo RepositoryInfo
+	if err := json.Unmarshal(data, &repoInfo); err != nil {
+		return nil, fmt.Errorf("error unmarshaling repository info: %v", err)
+	}
+
+	return &repoInfo, nil
+}
+
+// LatestRelease gets the newest release via GET {api}/releases/latest
+func (gt *GiteaInfo) LatestRelease(preRelease bool) (*Release, error) {
+	release, err := gt.getRelease(gt.api + "/releases/latest")
+	if err != nil {
+		return nil, err
+	}
+	if !preRelease && release.Prerelease {
+		return nil, fmt.Errorf("no releases found")
+	}
+	return release, nil
+}
+
+// ListReleases lists every release via GET {api}/releases
+func (gt *GiteaInfo) ListReleases() ([]*Release, error) {
+	data, err := gt.req(gt.api + "/releases")
+	if err != nil {
+		return nil, err
+	}
+
+	var releases []*Release
+	if err := json.Unmarshal(data, &releases); err != nil {
+		return nil, fmt.Errorf("error unmarshaling releases: %v", err)
+	}
+	for _, release := range releases {
+		release.URL = fmt.Sprintf("https://%s/%s/%s", gt.host, gt.owner, gt.repoName)
+	}
+
+	return releases, nil
+}
+
+// GetRelease fetches a single release by tag via GET {api}/releases/tags/{tag}
+func (gt *GiteaInfo) GetRelease(tagName string) (*Release, error) {
+	return gt.getRelease(fmt.Sprintf("%s/releases/tags/%s", gt.api, tagName))
+}
+
+// getRelease fetches and decodes a single release from api
+func (gt *GiteaInfo) getRelease(api string) (*Release, error) {
+	data, err := gt.req(api)
+	if err != nil {
+		return nil, err
+	}
+
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("error unmarshaling release: %v", err)
+	}
+	release.URL = fmt.Sprintf("https://%s/%s/%s", gt.host, gt.owner, gt.repoName)
+	return &release, nil
+}
+
+// DownloadAsset saves asset directly to destPath
+func (gt *GiteaInfo) DownloadAsset(asset *ReleaseAssets, destPath string) error {
+	return Download(asset.BrowserDownloadURL, destPath)
+}
+
+// req makes a request to the Gitea/Forgejo API
+func (gt *GiteaInfo) req(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+
+	for k, v := range gt.headers {
+		req.Header.Set(k, v)
+	}
+
+	if gt.token != "" {
+		req.Header.Set("Authorization", "token "+gt.token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ApiError{Message: fmt.Sprintf("API request failed with status: %d", resp.StatusCode)}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	return body, nil
+}
+
+// urlTemplateVars are the variables available to a URLTemplateInfo template
+type urlTemplateVars struct {
+	Version string
+	OS      string
+	Arch    string
+}
+
+// URLTemplateInfo is a generic ReleaseProvider for forges/mirrors with no dedicated
+// API support: the user pins a Go text/template download URL (via
+// `ir config --url-template`) with .Version/.OS/.Arch placeholders, and
+// GetRelease fills it in directly instead of discovering releases through an API.
+type URLTemplateInfo struct {
+	repoURL  string
+	template string
+}
+
+// NewURLTemplateInfo creates a handler that resolves downloads for repoURL by
+// filling in tmpl, a Go text/template string referencing .Version/.OS/.Arch
+func NewURLTemplateInfo(repoURL, tmpl string) (*URLTemplateInfo, error) {
+	return &URLTemplateInfo{repoURL: repoURL, template: tmpl}, nil
+}
+
+// Repository returns a minimal stub; URL-template sources have no repository API
+func (ut *URLTemplateInfo) Repository() (*RepositoryInfo, error) {
+	return &RepositoryInfo{Name: tagNameFromURL(ut.repoURL), HTMLURL: ut.repoURL}, nil
+}
+
+// LatestRelease always fails: a URL template has no API to discover the
+// latest version, so an explicit tag is required via GetRelease
+func (ut *URLTemplateInfo) LatestRelease(preRelease bool) (*Release, error) {
+	return nil, fmt.Errorf("%s requires an explicit --tag: it has no API to discover the latest release", ut.repoURL)
+}
+
+// ListReleases always fails: a URL template has no API to enumerate releases
+func (ut *URLTemplateInfo) ListReleases() ([]*Release, error) {
+	return nil, fmt.Errorf("%s has no API to list releases", ut.repoURL)
+}
+
+// GetRelease fills in the URL template for tagName
+func (ut *URLTemplateInfo) GetRelease(tagName string) (*Release, error) {
+	tmpl, err := template.New("url").Parse(ut.template)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing url template: %v", err)
+	}
+
+	osName, arch := GetSystemInfo()
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, urlTemplateVars{Version: tagName, OS: osName, Arch: arch}); err != nil {
+		return nil, fmt.Errorf("error rendering url template: %v", err)
+	}
+	downloadURL := buf.String()
+
+	return &Release{
+		URL:     ut.repoURL,
+		TagName: tagName,
+		Name:    tagName,
+		Assets: []ReleaseAssets{{
+			Name:               path.Base(downloadURL),
+			BrowserDownloadURL: downloadURL,
+		}},
+	}, nil
+}
+
+// DownloadAsset saves asset directly to destPath
+func (ut *URLTemplateInfo) DownloadAsset(asset *ReleaseAssets, destPath string) error {
+	return Download(asset.BrowserDownloadURL, destPath)
+}
+
+// resolveRelease fetches a single release from provider: a specific tag when
+// tagName is set, otherwise the latest release allowed by preRelease
+func resolveRelease(provider ReleaseProvider, tagName string, preRelease bool) (*Release, error) {
+	if tagName != "" {
+		release, err := provider.GetRelease(tagName)
+		if err != nil {
+			return nil, err
+		}
+		if !preRelease && release.Prerelease {
+			return nil, fmt.Errorf("no releases found")
+		}
+		return release, nil
+	}
+
+	return provider.LatestRelease(preRelease)
+}
+
 // GetRepoInfo creates the appropriate repository handler based on URL
-func GetRepoInfo(repoURL, token, gitlabToken string) (RepoInfo, error) {
-	if strings.Contains(repoURL, "github.com") {
-		return NewGitHubInfo(repoURL, token)
-	} else if strings.Contains(repoURL, "gitlab.com") {
-		return NewGitLabInfo(repoURL, gitlabToken)
+func GetRepoInfo(repoURL string, cfg *ToolConfig) (ReleaseProvider, error) {
+	u, err := url.Parse(strings.TrimSuffix(repoURL, "/"))
+	if err != nil || u.Host == "" {
+		return nil, &UnsupportedRepositoryError{Message: "Invalid repository URL: " + repoURL}
+	}
+
+	switch {
+	case u.Host == "github.com" || contains(cfg.GithubEnterpriseHosts, u.Host):
+		return NewGitHubInfo(repoURL, resolveHostToken(cfg, u.Host, cfg.Token))
+	case u.Host == "gitlab.com" || contains(cfg.GitlabHosts, u.Host):
+		return NewGitLabInfo(repoURL, resolveHostToken(cfg, u.Host, cfg.GitlabToken))
+	case u.Host == "codeberg.org" || contains(cfg.GiteaHosts, u.Host):
+		return NewGiteaInfo(repoURL, resolveHostToken(cfg, u.Host, ""))
+	}
+
+	if tmpl := cfg.URLTemplates[strings.TrimSuffix(repoURL, "/")]; tmpl != "" {
+		return NewURLTemplateInfo(repoURL, tmpl)
 	}
 
 	return nil, &UnsupportedRepositoryError{Message: "Unsupported repository type"}
 }
+
+// resolveHostToken returns the per-host token configured for host, falling
+// back to defaultToken (the plain github/gitlab token) when none is set
+func resolveHostToken(cfg *ToolConfig, host, defaultToken string) string {
+	if cfg.Tokens != nil {
+		if token, ok := cfg.Tokens[host]; ok && token != "" {
+			return token
+		}
+	}
+	return defaultToken
+}
+
+// contains reports whether host is present in hosts
+func contains(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if h == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AssetSelector picks the best release asset for the current system using
+// explicit rules layered on the built-in heuristic scorer. A zero-value
+// selector falls back to the original scoring behavior (see
+// defaultAssetSelector), so GetRelease/FindBestAsset stay unchanged for
+// existing callers.
+type AssetSelector struct {
+	// MustMatch is an ordered list of regexes a candidate name must all
+	// satisfy, e.g. "linux", "amd64|x86_64"
+	MustMatch []string `json:"must_match,omitempty"`
+	// MustNotMatch is an ordered list of regexes that disqualify a
+	// candidate if any of them match, e.g. "musl", "debug", "\\.sig$"
+	MustNotMatch []string `json:"must_not_match,omitempty"`
+	// PreferArchive nudges the tie-breaker towards archive assets
+	PreferArchive bool `json:"prefer_archive,omitempty"`
+	// PreferSmaller nudges the tie-breaker towards smaller assets
+	PreferSmaller bool `json:"prefer_smaller,omitempty"`
+	// PreferExtensions ranks candidates by how early their extension
+	// appears in this list, e.g. []string{".tar.gz", ".zip"}
+	PreferExtensions []string `json:"prefer_extensions,omitempty"`
+	// Pick is a glob (filepath.Match syntax) that, when set, short-circuits
+	// every other rule: the first asset whose name matches wins
+	Pick string `json:"pick,omitempty"`
+	// OS overrides the host-detected OS used for scoring/matching, e.g. to
+	// install a release for a different target platform
+	OS string `json:"os,omitempty"`
+	// Arch overrides the host-detected architecture used for scoring/matching
+	Arch string `json:"arch,omitempty"`
+	// Libc requires ("musl") or excludes ("glibc"/"gnu") the musl naming
+	// convention, for projects that publish both
+	Libc string `json:"libc,omitempty"`
+	// ExtraWords are additional words scored the same way platform/arch
+	// patterns are, e.g. a project-specific disambiguator
+	ExtraWords []string `json:"-"`
+}
+
+// defaultAssetSelector reproduces the historical GetRelease/FindBestAsset
+// behavior: no explicit include/exclude rules, archives and smaller files
+// preferred, no pick shortcut
+func defaultAssetSelector(extraWords []string) *AssetSelector {
+	// calculateAssetScore already applies the archive and size bonuses, so
+	// PreferArchive/PreferSmaller stay off here to avoid counting them twice
+	return &AssetSelector{
+		ExtraWords: extraWords,
+	}
+}
+
+// NewAssetSelector builds a selector from the tool config, optionally
+// overridden by a per-tool selector stored in state (release.Selector)
+func NewAssetSelector(cfg *ToolConfig, override *AssetSelector) *AssetSelector {
+	selector := defaultAssetSelector(nil)
+
+	if cfg != nil {
+		if len(cfg.MustMatch) > 0 {
+			selector.MustMatch = cfg.MustMatch
+		}
+		if len(cfg.MustNotMatch) > 0 {
+			selector.MustNotMatch = cfg.MustNotMatch
+		}
+		if len(cfg.PreferExtensions) > 0 {
+			selector.PreferExtensions = cfg.PreferExtensions
+		}
+	}
+
+	if override != nil {
+		if len(override.MustMatch) > 0 {
+			selector.MustMatch = override.MustMatch
+		}
+		if len(override.MustNotMatch) > 0 {
+			selector.MustNotMatch = override.MustNotMatch
+		}
+		if len(override.PreferExtensions) > 0 {
+			selector.PreferExtensions = override.PreferExtensions
+		}
+		if override.Pick != "" {
+			selector.Pick = override.Pick
+		}
+		if override.OS != "" {
+			selector.OS = override.OS
+		}
+		if override.Arch != "" {
+			selector.Arch = override.Arch
+		}
+		if override.Libc != "" {
+			selector.Libc = override.Libc
+		}
+	}
+
+	return selector
+}
+
+// mergeSelectorOverrides lets high's non-empty fields take priority over
+// low's, so an explicit per-call override (e.g. --os/--arch/--pick on this
+// invocation) wins over a persisted or repo-pinned one. Either may be nil;
+// returns nil if the merge ends up empty
+func mergeSelectorOverrides(low, high *AssetSelector) *AssetSelector {
+	merged := &AssetSelector{}
+	if low != nil {
+		*merged = *low
+	}
+	if high != nil {
+		if high.Pick != "" {
+			merged.Pick = high.Pick
+		}
+		if high.OS != "" {
+			merged.OS = high.OS
+		}
+		if high.Arch != "" {
+			merged.Arch = high.Arch
+		}
+		if high.Libc != "" {
+			merged.Libc = high.Libc
+		}
+		if len(high.MustMatch) > 0 {
+			merged.MustMatch = high.MustMatch
+		}
+		if len(high.MustNotMatch) > 0 {
+			merged.MustNotMatch = high.MustNotMatch
+		}
+	}
+
+	if merged.Pick == "" && merged.OS == "" && merged.Arch == "" && merged.Libc == "" &&
+		len(merged.MustMatch) == 0 && len(merged.MustNotMatch) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// repoOverrideSelector converts a config-pinned RepoOverride into an
+// AssetSelector override, or nil if o is nil
+func repoOverrideSelector(o *RepoOverride) *AssetSelector {
+	if o == nil {
+		return nil
+	}
+	return &AssetSelector{OS: o.OS, Arch: o.Arch, Libc: o.Libc, Pick: o.Pattern}
+}
+
+// Select picks the best asset among releases for osName/arch according to s
+func (s *AssetSelector) Select(releases []*Release, osName, arch string) (*ReleaseAssets, error) {
+	var candidates []ReleaseAssets
+	for _, release := range releases {
+		candidates = append(candidates, release.Assets...)
+	}
+	return s.SelectAssets(candidates, osName, arch)
+}
+
+// SelectAssets picks the best asset among a flat list of candidates
+func (s *AssetSelector) SelectAssets(assets []ReleaseAssets, osName, arch string) (*ReleaseAssets, error) {
+	if s.OS != "" {
+		osName = s.OS
+	}
+	if s.Arch != "" {
+		arch = normalizeArch(s.Arch)
+	}
+
+	if s.Pick != "" {
+		for i := range assets {
+			matched, err := filepath.Match(s.Pick, assets[i].Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pick pattern %q: %v", s.Pick, err)
+			}
+			if matched {
+				return &assets[i], nil
+			}
+		}
+		return nil, fmt.Errorf("no asset matched pick pattern %q", s.Pick)
+	}
+
+	mustMatchPatterns := s.MustMatch
+	mustNotMatchPatterns := s.MustNotMatch
+	switch strings.ToLower(s.Libc) {
+	case "musl":
+		mustMatchPatterns = append(append([]string{}, mustMatchPatterns...), "musl")
+	case "glibc", "gnu":
+		mustNotMatchPatterns = append(append([]string{}, mustNotMatchPatterns...), "musl")
+	}
+
+	mustMatch, err := compileAll(mustMatchPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid must-match pattern: %v", err)
+	}
+	mustNotMatch, err := compileAll(mustNotMatchPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid must-not-match pattern: %v", err)
+	}
+
+	var bestAsset *ReleaseAssets
+	var bestScore float64
+
+	for i := range assets {
+		asset := assets[i]
+		name := strings.ToLower(asset.Name)
+
+		if !matchesAll(mustMatch, name) || matchesAny(mustNotMatch, name) {
+			continue
+		}
+
+		score := calculateAssetScore(asset, osName, arch, s.ExtraWords)
+		if score == 0 && len(mustMatch) > 0 {
+			// An explicit MustMatch rule already vetted relevance;
+			// don't let the platform heuristic veto it back to zero.
+			score = 0.01
+		}
+
+		score = s.applyPreferences(score, asset)
+
+		if score > bestScore {
+			bestScore = score
+			bestAsset = &assets[i]
+		}
+	}
+
+	if bestAsset == nil {
+		return nil, fmt.Errorf("no suitable asset found for %s/%s", osName, arch)
+	}
+
+	return bestAsset, nil
+}
+
+// applyPreferences layers the selector's configurable tie-breakers on top of
+// the base score
+func (s *AssetSelector) applyPreferences(score float64, asset ReleaseAssets) float64 {
+	if s.PreferArchive && isArchiveAsset(asset) {
+		score *= 1.5
+	}
+	if s.PreferSmaller && asset.Size > 0 && asset.Size < 100*1024*1024 {
+		score += 0.1
+	}
+
+	name := strings.ToLower(asset.Name)
+	for rank, ext := range s.PreferExtensions {
+		if strings.HasSuffix(name, strings.ToLower(ext)) {
+			// Earlier entries in PreferExtensions rank higher
+			score += 1.0 / float64(rank+1)
+			break
+		}
+	}
+
+	return score
+}
+
+// compileAll compiles a list of regex sources, case-insensitively
+func compileAll(patterns []string) ([]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchesAll reports whether name matches every pattern
+func matchesAll(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if !re.MatchString(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesAny reports whether name matches at least one pattern
+func matchesAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// SelfUpdateOptions configures a self-update run
+type SelfUpdateOptions struct {
+	Token            string
+	PreRelease       bool
+	MinisignPubKey   string
+	CosignPubKey     string
+	RequireSignature bool
+}
+
+// SelfUpdate checks the tool's own GitHub repository for a newer release and,
+// if found, downloads, extracts and atomically replaces the running binary
+func SelfUpdate(ctx context.Context, opts SelfUpdateOptions) (updated bool, newVersion string, err error) {
+	repo, err := NewGitHubInfo(SelfRepoURL, opts.Token)
+	if err != nil {
+		return false, "", fmt.Errorf("error resolving self-update repository: %v", err)
+	}
+
+	latest, err := repo.LatestRelease(opts.PreRelease)
+	if err != nil {
+		return false, "", fmt.Errorf("error fetching latest release: %v", err)
+	}
+
+	newer, err := isNewerVersion(Version, latest.TagName)
+	if err != nil {
+		return false, "", fmt.Errorf("error comparing versions: %v", err)
+	}
+	if !newer {
+		return false, Version, nil
+	}
+
+	asset, err := GetRelease([]*Release{latest}, SelfRepoURL, nil)
+	if err != nil {
+		return false, "", fmt.Errorf("error selecting release asset: %v", err)
+	}
+
+	tempDir := filepath.Join(GetTempDir(), "install-release", "self-update")
+	if err := Mkdir(tempDir); err != nil {
+		return false, "", fmt.Errorf("error creating temp directory: %v", err)
+	}
+	defer RemoveDir(tempDir)
+
+	verifyOpts := VerifyOptions{
+		MinisignPubKey:   opts.MinisignPubKey,
+		CosignPubKey:     opts.CosignPubKey,
+		RequireSignature: opts.RequireSignature,
+	}
+	if _, err := ExtractRelease(asset, latest, tempDir, verifyOpts); err != nil {
+		return false, "", fmt.Errorf("error extracting release: %v", err)
+	}
+
+	executable, err := FindExecutable(tempDir)
+	if err != nil {
+		return false, "", fmt.Errorf("error finding executable in release: %v", err)
+	}
+
+	if err := os.Chmod(executable, 0755); err != nil {
+		return false, "", fmt.Errorf("error setting executable permissions: %v", err)
+	}
+	if !IsExecutable(executable) {
+		return false, "", fmt.Errorf("downloaded release is not executable")
+	}
+
+	currentPath, err := os.Executable()
+	if err != nil {
+		return false, "", fmt.Errorf("error locating current executable: %v", err)
+	}
+
+	if err := replaceExecutable(currentPath, executable); err != nil {
+		return false, "", fmt.Errorf("error replacing executable: %v", err)
+	}
+
+	return true, latest.TagName, nil
+}
+
+// replaceExecutable swaps the running binary for the freshly downloaded one.
+// On Unix, renaming onto the running path is safe: the OS keeps the old
+// inode alive for the process that is still executing it. On Windows the
+// running binary can't be overwritten directly, so it's moved aside first
+// and scheduled for deletion.
+func replaceExecutable(currentPath, newPath string) error {
+	if runtime.GOOS == "windows" {
+		oldPath := currentPath + ".old"
+		RemoveFile(oldPath) // best effort, leftover from a previous update
+		if err := os.Rename(currentPath, oldPath); err != nil {
+			return fmt.Errorf("error moving current executable aside: %v", err)
+		}
+		if err := CopyFile(newPath, currentPath); err != nil {
+			// Rollback: restore the original binary
+			os.Rename(oldPath, currentPath)
+			return fmt.Errorf("error installing new executable: %v", err)
+		}
+		// Best effort cleanup; a leftover .old file doesn't affect operation
+		RemoveFile(oldPath)
+		return nil
+	}
+
+	if err := os.Rename(newPath, currentPath); err != nil {
+		return fmt.Errorf("error installing new executable: %v", err)
+	}
+	return os.Chmod(currentPath, 0755)
+}
+
+// isNewerVersion reports whether candidate is a newer semver than current.
+// Both are accepted with or without a leading "v" and an optional
+// "-pre" style suffix, which is ignored for comparison purposes.
+func isNewerVersion(current, candidate string) (bool, error) {
+	currentParts, err := parseSemver(current)
+	if err != nil {
+		return false, err
+	}
+	candidateParts, err := parseSemver(candidate)
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; i < 3; i++ {
+		if candidateParts[i] != currentParts[i] {
+			return candidateParts[i] > currentParts[i], nil
+		}
+	}
+
+	return false, nil
+}
+
+// parseSemver parses "v1.2.3" or "1.2.3-pre" into [major, minor, patch]
+func parseSemver(version string) ([3]int, error) {
+	var parts [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	version = strings.SplitN(version, "-", 2)[0]
+
+	segments := strings.Split(version, ".")
+	if len(segments) != 3 {
+		return parts, fmt.Errorf("invalid semver: %s", version)
+	}
+
+	for i, segment := range segments {
+		n, err := strconv.Atoi(segment)
+		if err != nil {
+			return parts, fmt.Errorf("invalid semver segment %q: %v", segment, err)
+		}
+		parts[i] = n
+	}
+
+	return parts, nil
+}
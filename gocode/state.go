@@ -1,31 +1,41 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
+	"sync"
+	"time"
 )
 
-// StateManager handles the state of installed tools - matches Python State class
+// StateManager handles the state of installed tools - matches Python State class.
+// Persistence is delegated to a StateDriver (file, dir or sqlite); sm.state is
+// an in-memory cache kept in sync with it so Items()/Keys()/Get() stay cheap.
+// mu guards sm.state against concurrent goroutines sharing one StateManager;
+// cross-process safety is the StateDriver's job (see fileStateDriver's flock)
 type StateManager struct {
-	state     State
-	stateFile string
+	mu     sync.Mutex
+	state  State
+	driver StateDriver
 }
 
-// NewStateManager creates a new state manager - matches Python State.__init__
+// NewStateManager creates a new state manager, opening the StateDriver
+// selected via `ir config --state-backend` (the single-file state.json
+// backend by default)
 func NewStateManager() *StateManager {
-	stateFile := StatePath()
+	config := NewConfigManager()
+	_ = config.Load() // best-effort; an unreadable config just falls back to the file driver
 
-	// Ensure directory exists - matches Python platform_path behavior
-	dir := filepath.Dir(stateFile)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		fmt.Printf("Error creating directory %s: %v\n", dir, err)
+	driver, err := NewStateDriver(config)
+	if err != nil {
+		fmt.Printf("Error opening state backend: %v\n", err)
+		if driver, err = newFileStateDriver(StatePath()); err != nil {
+			fmt.Printf("Error opening fallback state backend: %v\n", err)
+			driver = &fileStateDriver{path: StatePath()}
+		}
 	}
 
 	sm := &StateManager{
-		state:     make(State),
-		stateFile: stateFile,
+		state:  make(State),
+		driver: driver,
 	}
 
 	// Auto-load like Python version
@@ -34,138 +44,76 @@ func NewStateManager() *StateManager {
 	return sm
 }
 
-// Load loads the state from file - matches Python State.load()
+// Load loads the state from the driver - matches Python State.load()
 func (sm *StateManager) Load() error {
-	if _, err := os.Stat(sm.stateFile); os.IsNotExist(err) {
-		// File doesn't exist, start with empty state
-		return nil
-	}
-
-	file, err := os.Open(sm.stateFile)
+	state, err := sm.driver.Load()
 	if err != nil {
-		return fmt.Errorf("error opening state file: %v", err)
-	}
-	defer file.Close()
-
-	// Load as raw JSON first
-	var rawState map[string]interface{}
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&rawState); err != nil {
-		return fmt.Errorf("error decoding state file: %v", err)
-	}
-
-	// Convert to proper Release objects (like Python FilterDataclass)
-	for key, value := range rawState {
-		if valueMap, ok := value.(map[string]interface{}); ok {
-			release := &Release{}
-
-			// Manual mapping to match Python dataclass filtering
-			if url, ok := valueMap["url"].(string); ok {
-				release.URL = url
-			}
-			if name, ok := valueMap["name"].(string); ok {
-				release.Name = name
-			}
-			if tagName, ok := valueMap["tag_name"].(string); ok {
-				release.TagName = tagName
-			}
-			if prerelease, ok := valueMap["prerelease"].(bool); ok {
-				release.Prerelease = prerelease
-			}
-			if publishedAt, ok := valueMap["published_at"].(string); ok {
-				release.PublishedAt = publishedAt
-			}
-			if holdUpdate, ok := valueMap["hold_update"].(bool); ok {
-				release.HoldUpdate = holdUpdate
-			}
-
-			// Handle assets array
-			if assetsInterface, ok := valueMap["assets"].([]interface{}); ok {
-				var assets []ReleaseAssets
-				for _, assetInterface := range assetsInterface {
-					if assetMap, ok := assetInterface.(map[string]interface{}); ok {
-						asset := ReleaseAssets{}
-
-						// Map asset fields
-						if url, ok := assetMap["browser_download_url"].(string); ok {
-							asset.BrowserDownloadURL = url
-						}
-						if contentType, ok := assetMap["content_type"].(string); ok {
-							asset.ContentType = contentType
-						}
-						if createdAt, ok := assetMap["created_at"].(string); ok {
-							asset.CreatedAt = createdAt
-						}
-						if downloadCount, ok := assetMap["download_count"].(float64); ok {
-							asset.DownloadCount = int(downloadCount)
-						}
-						if id, ok := assetMap["id"].(float64); ok {
-							asset.ID = int(id)
-						}
-						if name, ok := assetMap["name"].(string); ok {
-							asset.Name = name
-						}
-						if nodeID, ok := assetMap["node_id"].(string); ok {
-							asset.NodeID = nodeID
-						}
-						if size, ok := assetMap["size"].(float64); ok {
-							asset.Size = int(size)
-						}
-						if state, ok := assetMap["state"].(string); ok {
-							asset.State = state
-						}
-						if updatedAt, ok := assetMap["updated_at"].(string); ok {
-							asset.UpdatedAt = updatedAt
-						}
-
-						assets = append(assets, asset)
-					}
-				}
-				release.Assets = assets
-			}
-
-			sm.state[key] = release
-		}
+		return err
 	}
-
+	sm.mu.Lock()
+	sm.state = state
+	sm.mu.Unlock()
 	return nil
 }
 
-// Save saves the state to file - matches Python State.save()
+// Reload re-reads the state from the driver, discarding the in-memory cache.
+// It's equivalent to Load() today, but gives long-lived callers (a future
+// daemon mode, which would hold one StateManager open across many commands
+// instead of one per process) an explicit way to pick up edits written by
+// another `ir` invocation since this one started
+func (sm *StateManager) Reload() error {
+	return sm.Load()
+}
+
+// Save rewrites every item through the driver - matches Python State.save().
+// SetItem/DelItem are the cheaper per-tool path; this is for callers that
+// mutated sm.state directly via Set()
 func (sm *StateManager) Save() error {
-	file, err := os.Create(sm.stateFile)
-	if err != nil {
-		return fmt.Errorf("error creating state file: %v", err)
+	sm.mu.Lock()
+	snapshot := make(State, len(sm.state))
+	for key, release := range sm.state {
+		snapshot[key] = release
 	}
-	defer file.Close()
+	sm.mu.Unlock()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(sm.state); err != nil {
-		return fmt.Errorf("error encoding state: %v", err)
+	for key, release := range snapshot {
+		if err := sm.driver.SetItem(key, release); err != nil {
+			return err
+		}
 	}
-
 	return nil
 }
 
 // Get retrieves a value from state - matches Python State.get()
 func (sm *StateManager) Get(key string) *Release {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	return sm.state[key] // Returns nil if not found, like Python .get()
 }
 
 // Set sets a value in state - matches Python State.set() (no auto-save)
 func (sm *StateManager) Set(key string, value *Release) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	sm.state[key] = value
 	// No auto-save here, like Python version
 }
 
-// Items returns all items in the state - matches Python State.items()
+// Items returns a snapshot copy of the state - matches Python State.items()
 func (sm *StateManager) Items() map[string]*Release {
-	return sm.state
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	items := make(map[string]*Release, len(sm.state))
+	for k, v := range sm.state {
+		items[k] = v
+	}
+	return items
 }
 
 // Keys returns all keys in the state - matches Python State.keys()
 func (sm *StateManager) Keys() []string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	keys := make([]string, 0, len(sm.state))
 	for k := range sm.state {
 		keys = append(keys, k)
@@ -175,36 +123,74 @@ func (sm *StateManager) Keys() []string {
 
 // Pop removes and returns a value from state - matches Python State.pop()
 func (sm *StateManager) Pop(key string) *Release {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	value := sm.state[key]
 	delete(sm.state, key)
 	return value
 }
 
-// SetItem sets a value and auto-saves - matches Python State.__setitem__
+// SetItem sets a value and persists it through the driver - matches Python State.__setitem__
 func (sm *StateManager) SetItem(key string, value *Release) {
+	sm.mu.Lock()
 	sm.state[key] = value
-	sm.Save() // Auto-save like Python __setitem__
+	sm.mu.Unlock()
+
+	if err := sm.driver.SetItem(key, value); err != nil {
+		fmt.Printf("Error saving state: %v\n", err)
+	}
 }
 
-// DelItem removes a value and auto-saves - matches Python State.__delitem__
+// DelItem removes a value and persists the removal through the driver - matches Python State.__delitem__
 func (sm *StateManager) DelItem(key string) {
+	sm.mu.Lock()
 	delete(sm.state, key)
-	sm.Save() // Auto-save like Python __delitem__
+	sm.mu.Unlock()
+
+	if err := sm.driver.DelItem(key); err != nil {
+		fmt.Printf("Error saving state: %v\n", err)
+	}
 }
 
 // Contains checks if key exists - matches Python State.__contains__
 func (sm *StateManager) Contains(key string) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	_, exists := sm.state[key]
 	return exists
 }
 
 // Len returns the number of items - matches Python State.__len__
 func (sm *StateManager) Len() int {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	return len(sm.state)
 }
 
+// Close releases the underlying StateDriver's resources (e.g. closes the
+// sqlite backend's DB handle)
+func (sm *StateManager) Close() error {
+	return sm.driver.Close()
+}
+
+// Migrate upgrades the on-disk state document to CurrentStateSchemaVersion if
+// it isn't already, reloading sm.state and reporting whether anything
+// changed, backing `ir state migrate`
+func (sm *StateManager) Migrate() (bool, error) {
+	migrated, err := sm.driver.Migrate()
+	if err != nil {
+		return false, err
+	}
+	if err := sm.Load(); err != nil {
+		return migrated, err
+	}
+	return migrated, nil
+}
+
 // GetByName retrieves a value from state by tool name using IrKey - matches Python usage
 func (sm *StateManager) GetByName(name string) (*Release, string, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	for key, release := range sm.state {
 		irKey := ParseIrKey(key)
 		if irKey.Name == name {
@@ -222,12 +208,47 @@ func (sm *StateManager) SetByName(url, name string, value *Release) {
 
 // DeleteByName removes a value from state by tool name and auto-saves
 func (sm *StateManager) DeleteByName(name string) bool {
-	for key := range sm.state {
-		irKey := ParseIrKey(key)
-		if irKey.Name == name {
-			sm.DelItem(key) // Use DelItem for auto-save
-			return true
+	sm.mu.Lock()
+	var key string
+	found := false
+	for k := range sm.state {
+		if ParseIrKey(k).Name == name {
+			key = k
+			found = true
+			break
 		}
 	}
-	return false
+	sm.mu.Unlock()
+
+	if !found {
+		return false
+	}
+	sm.DelItem(key) // Use DelItem for auto-save
+	return true
+}
+
+// AppendHistory appends a new HistoryRevision to release's history (carrying
+// over release.TagName/VerifiedDigest/Assets as of the call) and trims it
+// down to the last MaxHistoryRevisions entries. Callers still need to
+// persist release via SetItem/SetByName
+func AppendHistory(release *Release, description string) *Release {
+	next := 1
+	if len(release.History) > 0 {
+		next = release.History[len(release.History)-1].Revision + 1
+	}
+
+	release.History = append(release.History, HistoryRevision{
+		Revision:    next,
+		TagName:     release.TagName,
+		InstalledAt: time.Now().UTC().Format(time.RFC3339),
+		Description: description,
+		Digest:      release.VerifiedDigest,
+		Assets:      release.Assets,
+	})
+
+	if len(release.History) > MaxHistoryRevisions {
+		release.History = release.History[len(release.History)-MaxHistoryRevisions:]
+	}
+
+	return release
 }
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	_ "modernc.org/sqlite"
+)
+
+// StateDriver is the storage backend behind StateManager. The default,
+// fileStateDriver, keeps every tool in a single state.json and rewrites the
+// whole file on every mutation; dirStateDriver and sqliteStateDriver trade
+// that for per-tool writes, so an upgrade/rollback on one tool doesn't
+// serialize or lock the records of every other tool. Modeled on the way
+// Helm's storage package puts release records behind pluggable drivers
+// (configmap, secret, memory) rather than a single blob.
+type StateDriver interface {
+	// Load returns every stored release, keyed by its IrKey string
+	Load() (State, error)
+	// SetItem persists a single release under key, creating or overwriting it
+	SetItem(key string, release *Release) error
+	// DelItem removes a single release
+	DelItem(key string) error
+	// Close releases any resources held by the driver (e.g. a DB handle)
+	Close() error
+	// Migrate upgrades the on-disk document to CurrentStateSchemaVersion if
+	// it isn't already, returning whether anything was migrated. Backends
+	// with no legacy flat-file format (dir, sqlite) are no-ops
+	Migrate() (bool, error)
+}
+
+// NewStateDriver opens the StateDriver selected by config, defaulting to the
+// single-file JSON store when config is nil or unset
+func NewStateDriver(cm *ConfigManager) (StateDriver, error) {
+	backend := StateBackendFile
+	if cm != nil {
+		backend = cm.GetStateBackend()
+	}
+
+	switch backend {
+	case StateBackendDir:
+		return newDirStateDriver(StateDirPath())
+	case StateBackendSQLite:
+		return newSQLiteStateDriver(StateDBPath())
+	case StateBackendFile, "":
+		return newFileStateDriver(StatePath())
+	default:
+		return nil, fmt.Errorf("unknown state backend %q", backend)
+	}
+}
+
+// fileStateDriver stores every release in a single JSON file, matching the
+// original Python State class layout. mu guards the read-modify-write cycle
+// in SetItem/DelItem against concurrent goroutines in this process; lockFile
+// holds an OS-level advisory lock (flock) for the life of the driver, so a
+// second `ir` process (e.g. a cron-triggered upgrade racing a user's `ir
+// get`) blocks instead of interleaving writes to the same state file
+type fileStateDriver struct {
+	path     string
+	lockFile *os.File
+	mu       sync.Mutex
+}
+
+// newFileStateDriver opens path and blocks until it acquires an exclusive
+// flock on "<path>.lock", released by Close(). flock is POSIX-only: Windows
+// support (chunk3-5) will need a LockFileEx-based variant here
+func newFileStateDriver(path string) (*fileStateDriver, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory %s: %v", filepath.Dir(path), err)
+	}
+
+	lockFile, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("error opening state lock file: %v", err)
+	}
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		lockFile.Close()
+		return nil, fmt.Errorf("error locking state file: %v", err)
+	}
+
+	return &fileStateDriver{path: path, lockFile: lockFile}, nil
+}
+
+func (d *fileStateDriver) Load() (State, error) {
+	state, _, err := d.loadDocument()
+	return state, err
+}
+
+// loadDocument reads the state file and migrates it up to
+// CurrentStateSchemaVersion if needed, decoding releases by a plain
+// json.Unmarshal into *Release instead of the manual field-by-field copying
+// the driver used before schema versioning existed. It returns the decoded
+// state and whether a migration actually ran, backing up and rewriting the
+// file in that case.
+func (d *fileStateDriver) loadDocument() (State, bool, error) {
+	if _, err := os.Stat(d.path); os.IsNotExist(err) {
+		return make(State), false, nil
+	}
+
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return nil, false, fmt.Errorf("error opening state file: %v", err)
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, false, fmt.Errorf("error decoding state file: %v", err)
+	}
+
+	raw, migrated, err := runMigrations(raw, stateMigrations, CurrentStateSchemaVersion)
+	if err != nil {
+		return nil, false, fmt.Errorf("error migrating state file: %v", err)
+	}
+
+	releasesRaw, _ := raw["releases"].(map[string]interface{})
+	releasesData, err := json.Marshal(releasesRaw)
+	if err != nil {
+		return nil, false, fmt.Errorf("error re-encoding state: %v", err)
+	}
+
+	state := make(State, len(releasesRaw))
+	if err := json.Unmarshal(releasesData, &state); err != nil {
+		return nil, false, fmt.Errorf("error decoding releases: %v", err)
+	}
+
+	if migrated {
+		if err := backupFile(d.path); err != nil {
+			return nil, false, err
+		}
+		if err := d.save(state); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return state, migrated, nil
+}
+
+// Migrate runs loadDocument (which migrates as a side effect) and reports
+// whether the on-disk state file was upgraded, backing `ir state migrate`
+func (d *fileStateDriver) Migrate() (bool, error) {
+	_, migrated, err := d.loadDocument()
+	return migrated, err
+}
+
+// save writes state to "<path>.tmp" and renames it over path, so a reader
+// never observes a partially-written file even if the process is killed
+// mid-write
+func (d *fileStateDriver) save(state State) error {
+	if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %v", filepath.Dir(d.path), err)
+	}
+
+	tmpPath := d.path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error creating state file: %v", err)
+	}
+
+	doc := map[string]interface{}{
+		"schema_version": CurrentStateSchemaVersion,
+		"releases":       state,
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		file.Close()
+		return fmt.Errorf("error encoding state: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing state file: %v", err)
+	}
+
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		return fmt.Errorf("error replacing state file: %v", err)
+	}
+	return nil
+}
+
+// SetItem re-reads the whole file, updates one key and rewrites it; a
+// single-file store has no cheaper option. mu serializes this
+// read-modify-write cycle against other goroutines in this process; the
+// flock held since newFileStateDriver serializes it against other processes
+func (d *fileStateDriver) SetItem(key string, release *Release) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, err := d.Load()
+	if err != nil {
+		return err
+	}
+	state[key] = release
+	return d.save(state)
+}
+
+func (d *fileStateDriver) DelItem(key string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, err := d.Load()
+	if err != nil {
+		return err
+	}
+	delete(state, key)
+	return d.save(state)
+}
+
+// Close releases the flock acquired by newFileStateDriver
+func (d *fileStateDriver) Close() error {
+	if d.lockFile == nil {
+		return nil
+	}
+	unlockErr := syscall.Flock(int(d.lockFile.Fd()), syscall.LOCK_UN)
+	closeErr := d.lockFile.Close()
+	if unlockErr != nil {
+		return fmt.Errorf("error unlocking state file: %v", unlockErr)
+	}
+	return closeErr
+}
+
+// dirStateDriver stores each tool as its own "<sha256(key)>.json" file under
+// dir, so writing one tool's state never touches another's file and never
+// needs to hold a lock across the whole store
+type dirStateDriver struct {
+	dir string
+}
+
+// dirStateRecord wraps a Release with the original (unhashed) key, since the
+// filename itself is a one-way hash of it
+type dirStateRecord struct {
+	Key     string   `json:"key"`
+	Release *Release `json:"release"`
+}
+
+func newDirStateDriver(dir string) (*dirStateDriver, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating state directory %s: %v", dir, err)
+	}
+	return &dirStateDriver{dir: dir}, nil
+}
+
+// dirStateFilename derives a filesystem-safe filename from a state key
+// (an "url#name" IrKey string, which can contain '/' and ':')
+func dirStateFilename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+func (d *dirStateDriver) Load() (State, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(State), nil
+		}
+		return nil, fmt.Errorf("error reading state directory: %v", err)
+	}
+
+	state := make(State, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(d.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", entry.Name(), err)
+		}
+		var record dirStateRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("error decoding %s: %v", entry.Name(), err)
+		}
+		state[record.Key] = record.Release
+	}
+	return state, nil
+}
+
+func (d *dirStateDriver) SetItem(key string, release *Release) error {
+	record := dirStateRecord{Key: key, Release: release}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding state: %v", err)
+	}
+	path := filepath.Join(d.dir, dirStateFilename(key))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return nil
+}
+
+func (d *dirStateDriver) DelItem(key string) error {
+	path := filepath.Join(d.dir, dirStateFilename(key))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing %s: %v", path, err)
+	}
+	return nil
+}
+
+func (d *dirStateDriver) Close() error {
+	return nil
+}
+
+// Migrate is a no-op: dirStateDriver didn't exist before schema versioning,
+// so there's no legacy format to upgrade from
+func (d *dirStateDriver) Migrate() (bool, error) {
+	return false, nil
+}
+
+// sqliteStateDriver stores each tool as a row in a SQLite database, via
+// modernc.org/sqlite's pure-Go driver so installing/building install-release
+// doesn't need cgo
+type sqliteStateDriver struct {
+	db *sql.DB
+}
+
+func newSQLiteStateDriver(path string) (*sqliteStateDriver, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating directory %s: %v", filepath.Dir(path), err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening state database: %v", err)
+	}
+
+	const schema = `CREATE TABLE IF NOT EXISTS releases (
+		key  TEXT PRIMARY KEY,
+		data TEXT NOT NULL
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating state schema: %v", err)
+	}
+
+	return &sqliteStateDriver{db: db}, nil
+}
+
+func (d *sqliteStateDriver) Load() (State, error) {
+	rows, err := d.db.Query(`SELECT key, data FROM releases`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying state database: %v", err)
+	}
+	defer rows.Close()
+
+	state := make(State)
+	for rows.Next() {
+		var key, data string
+		if err := rows.Scan(&key, &data); err != nil {
+			return nil, fmt.Errorf("error scanning state row: %v", err)
+		}
+		var release Release
+		if err := json.Unmarshal([]byte(data), &release); err != nil {
+			return nil, fmt.Errorf("error decoding state row %s: %v", key, err)
+		}
+		state[key] = &release
+	}
+	return state, rows.Err()
+}
+
+func (d *sqliteStateDriver) SetItem(key string, release *Release) error {
+	data, err := json.Marshal(release)
+	if err != nil {
+		return fmt.Errorf("error encoding state: %v", err)
+	}
+	_, err = d.db.Exec(
+		`INSERT INTO releases (key, data) VALUES (?, ?)
+		 ON CONFLICT(key) DO UPDATE SET data = excluded.data`,
+		key, string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("error writing state row %s: %v", key, err)
+	}
+	return nil
+}
+
+func (d *sqliteStateDriver) DelItem(key string) error {
+	if _, err := d.db.Exec(`DELETE FROM releases WHERE key = ?`, key); err != nil {
+		return fmt.Errorf("error deleting state row %s: %v", key, err)
+	}
+	return nil
+}
+
+func (d *sqliteStateDriver) Close() error {
+	return d.db.Close()
+}
+
+// Migrate is a no-op: sqliteStateDriver didn't exist before schema
+// versioning, so there's no legacy format to upgrade from
+func (d *sqliteStateDriver) Migrate() (bool, error) {
+	return false, nil
+}
@@ -45,13 +45,67 @@ func (ra *ReleaseAssets) SizeMB() float64 {
 
 // Release represents a GitHub/GitLab release
 type Release struct {
-	URL         string          `json:"url"`
-	Name        string          `json:"name"`
+	ID              int               `json:"id,omitempty"`
+	URL             string            `json:"url"`
+	Name            string            `json:"name"`
+	TagName         string            `json:"tag_name"`
+	TargetCommitish string            `json:"target_commitish,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	Draft           bool              `json:"draft,omitempty"`
+	Prerelease      bool              `json:"prerelease"`
+	PublishedAt     string            `json:"published_at"`
+	HTMLURL         string            `json:"html_url,omitempty"`
+	Assets          []ReleaseAssets   `json:"assets"`
+	HoldUpdate      bool              `json:"hold_update,omitempty"`
+	Selector        *AssetSelector    `json:"selector,omitempty"`
+	VerifiedDigest  string            `json:"verified_digest,omitempty"`
+	Uninstalled     bool              `json:"uninstalled,omitempty"`
+	History         []HistoryRevision `json:"history,omitempty"`
+	Hooks           *HookSet          `json:"hooks,omitempty"`
+
+	// Binaries lists every installed binary's name, resolved by
+	// ResolveBinaries, so remove/upgrade know exactly which files to
+	// touch for a multi-binary package instead of re-guessing. Empty for
+	// single-binary tools predating this field, which install under the
+	// tool's own name.
+	Binaries []string `json:"binaries,omitempty"`
+
+	// AuxiliaryPaths lists every shell completion, man page, and PATH shim
+	// installed alongside Binaries (completions and man pages found by
+	// ScanAuxiliaryFiles, the shim created by EnsureBinOnPath), so Remove
+	// can delete them without re-scanning the (now gone) extracted archive
+	AuxiliaryPaths []string `json:"auxiliary_paths,omitempty"`
+}
+
+// HookSet lists the shell commands run at each lifecycle point around
+// install/upgrade/remove. A per-tool override (Release.Hooks), when set,
+// replaces the global set (ToolConfig.Hooks) as a whole: events it leaves
+// empty run no commands rather than falling back to the global ones
+type HookSet struct {
+	PreInstall  []string `json:"pre_install,omitempty"`
+	PostInstall []string `json:"post_install,omitempty"`
+	PreUpgrade  []string `json:"pre_upgrade,omitempty"`
+	PostUpgrade []string `json:"post_upgrade,omitempty"`
+	PreRemove   []string `json:"pre_remove,omitempty"`
+	PostRemove  []string `json:"post_remove,omitempty"`
+}
+
+// NotifyConfig configures outbound event notifications fired alongside hooks
+type NotifyConfig struct {
+	WebhookURL string `json:"webhook_url,omitempty"`
+	SlackURL   string `json:"slack_url,omitempty"`
+}
+
+// HistoryRevision records one lifecycle step for a tool (install, upgrade,
+// rollback or removal), letting `ir history`/`ir rollback` inspect or
+// restore a previous state
+type HistoryRevision struct {
+	Revision    int             `json:"revision"`
 	TagName     string          `json:"tag_name"`
-	Prerelease  bool            `json:"prerelease"`
-	PublishedAt string          `json:"published_at"`
-	Assets      []ReleaseAssets `json:"assets"`
-	HoldUpdate  bool            `json:"hold_update,omitempty"`
+	InstalledAt string          `json:"installed_at"`
+	Description string          `json:"description"`
+	Digest      string          `json:"verified_digest,omitempty"`
+	Assets      []ReleaseAssets `json:"assets,omitempty"`
 }
 
 // PublishedDT returns the published date as time.Time
@@ -73,10 +127,38 @@ func (r *Release) PublishedDT() (time.Time, error) {
 
 // ToolConfig represents the configuration for the tool
 type ToolConfig struct {
-	Token       string `json:"token,omitempty"`
-	GitlabToken string `json:"gitlab_token,omitempty"`
-	Path        string `json:"path,omitempty"`
-	PreRelease  bool   `json:"pre_release,omitempty"`
+	SchemaVersion         int                     `json:"schema_version,omitempty"`
+	Token                 string                  `json:"token,omitempty"`
+	GitlabToken           string                  `json:"gitlab_token,omitempty"`
+	Path                  string                  `json:"path,omitempty"`
+	PreRelease            bool                    `json:"pre_release,omitempty"`
+	MinisignPubKey        string                  `json:"minisign_pub_key,omitempty"`
+	CosignPubKey          string                  `json:"cosign_pub_key,omitempty"`
+	RequireSignature      bool                    `json:"require_signature,omitempty"`
+	GithubEnterpriseHosts []string                `json:"github_enterprise_hosts,omitempty"`
+	GitlabHosts           []string                `json:"gitlab_hosts,omitempty"`
+	GiteaHosts            []string                `json:"gitea_hosts,omitempty"`
+	URLTemplates          map[string]string       `json:"url_templates,omitempty"`
+	Tokens                map[string]string       `json:"tokens,omitempty"`
+	MustMatch             []string                `json:"must_match,omitempty"`
+	MustNotMatch          []string                `json:"must_not_match,omitempty"`
+	PreferExtensions      []string                `json:"prefer_extensions,omitempty"`
+	TrustedKeys           map[string]string       `json:"trusted_keys,omitempty"`
+	AssetOverrides        map[string]RepoOverride `json:"asset_overrides,omitempty"`
+	StateBackend          string                  `json:"state_backend,omitempty"`
+	Hooks                 HookSet                 `json:"hooks,omitempty"`
+	Notify                NotifyConfig            `json:"notify,omitempty"`
+}
+
+// RepoOverride pins an asset-selection override for a specific repository,
+// set via `ir config --asset-override` and applied on top of the default
+// host-detected os/arch (e.g. to install an arm64/musl binary from an
+// x86_64/glibc machine for a remote target)
+type RepoOverride struct {
+	OS      string `json:"os,omitempty"`
+	Arch    string `json:"arch,omitempty"`
+	Libc    string `json:"libc,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
 }
 
 // IrKey represents a key in the state
@@ -3,6 +3,7 @@ package main
 import (
 	"archive/tar"
 	"archive/zip"
+	"compress/bzip2"
 	"compress/gzip"
 	"fmt"
 	"io"
@@ -14,8 +15,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bodgit/sevenzip"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
 // Download downloads a file from URL to the specified path
@@ -46,21 +50,97 @@ func Download(url, path string) error {
 	return nil
 }
 
-// Extract extracts an archive file to the specified directory
+// Extract extracts an archive file to the specified directory, streaming the
+// contents straight from the decompressor without buffering the whole file
 func Extract(archivePath, extractPath string) error {
-	if strings.HasSuffix(archivePath, ".tar.gz") || strings.HasSuffix(archivePath, ".tgz") {
-		return extractTarGz(archivePath, extractPath)
-	} else if strings.HasSuffix(archivePath, ".zip") {
-		return extractZip(archivePath, extractPath)
-	} else if strings.HasSuffix(archivePath, ".tar") {
-		return extractTar(archivePath, extractPath)
+	return SafeExtract(archivePath, extractPath, ExtractOptions{})
+}
+
+// ExtractOptions bounds the work SafeExtract is willing to do, guarding
+// against zip-bomb style archives. A zero value is replaced by sane
+// defaults (see withDefaults).
+type ExtractOptions struct {
+	// MaxDecompressedSize caps the total bytes written across all entries
+	MaxDecompressedSize int64
+	// MaxEntries caps the number of entries processed
+	MaxEntries int
+}
+
+const (
+	defaultMaxDecompressedSize = 10 * 1024 * 1024 * 1024 // 10 GB
+	defaultMaxEntries          = 100000
+)
+
+func (o ExtractOptions) withDefaults() ExtractOptions {
+	if o.MaxDecompressedSize <= 0 {
+		o.MaxDecompressedSize = defaultMaxDecompressedSize
+	}
+	if o.MaxEntries <= 0 {
+		o.MaxEntries = defaultMaxEntries
+	}
+	return o
+}
+
+// SafeExtract extracts archivePath into extractPath, rejecting any entry
+// (including symlink/hardlink targets) whose resolved path escapes
+// extractPath (Zip Slip), and enforcing opts' decompressed-size and
+// entry-count limits. It supports every suffix in ArchiveExtensions,
+// including plain single-file .gz and .xz; Extract is a wrapper around it.
+func SafeExtract(archivePath, extractPath string, opts ExtractOptions) error {
+	opts = opts.withDefaults()
+	name := strings.ToLower(archivePath)
+
+	switch {
+	case strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz"):
+		return extractTarGz(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".tar.bz2") || strings.HasSuffix(name, ".tbz2"):
+		return extractTarBz2(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".tar.xz") || strings.HasSuffix(name, ".txz"):
+		return extractTarXz(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".tar.zst") || strings.HasSuffix(name, ".tzst"):
+		return extractTarZst(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".zip"):
+		return extractZip(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".7z"):
+		return extract7z(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".tar"):
+		return extractTar(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".gz"):
+		return extractSingleGz(archivePath, extractPath, opts)
+	case strings.HasSuffix(name, ".xz"):
+		return extractSingleXz(archivePath, extractPath, opts)
 	}
 
 	return fmt.Errorf("unsupported archive format")
 }
 
-// extractTarGz extracts a .tar.gz file
-func extractTarGz(archivePath, extractPath string) error {
+// safeJoin joins base and name, rejecting any result that escapes base once
+// ".." components are resolved (Zip Slip)
+func safeJoin(base, name string) (string, error) {
+	target := filepath.Join(base, name)
+	cleanBase := filepath.Clean(base)
+	if target != cleanBase && !strings.HasPrefix(target, cleanBase+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path %q escapes extraction directory", name)
+	}
+	return target, nil
+}
+
+// safeSymlinkTarget rejects a symlink, whose entry lives in dir, whose link
+// text is linkname, if it would resolve to a path outside base
+func safeSymlinkTarget(base, dir, linkname string) error {
+	resolved := filepath.Clean(linkname)
+	if !filepath.IsAbs(linkname) {
+		resolved = filepath.Clean(filepath.Join(dir, linkname))
+	}
+	cleanBase := filepath.Clean(base)
+	if resolved != cleanBase && !strings.HasPrefix(resolved, cleanBase+string(os.PathSeparator)) {
+		return fmt.Errorf("symlink target %q escapes extraction directory", linkname)
+	}
+	return nil
+}
+
+// extractTarGz extracts a .tar.gz/.tgz file
+func extractTarGz(archivePath, extractPath string, opts ExtractOptions) error {
 	file, err := os.Open(archivePath)
 	if err != nil {
 		return fmt.Errorf("error opening archive: %v", err)
@@ -73,7 +153,73 @@ func extractTarGz(archivePath, extractPath string) error {
 	}
 	defer gzr.Close()
 
-	tr := tar.NewReader(gzr)
+	return extractTarStream(gzr, extractPath, opts)
+}
+
+// extractTarBz2 extracts a .tar.bz2/.tbz2 file
+func extractTarBz2(archivePath, extractPath string, opts ExtractOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %v", err)
+	}
+	defer file.Close()
+
+	return extractTarStream(bzip2.NewReader(file), extractPath, opts)
+}
+
+// extractTarXz extracts a .tar.xz/.txz file
+func extractTarXz(archivePath, extractPath string, opts ExtractOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %v", err)
+	}
+	defer file.Close()
+
+	xzr, err := xz.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("error creating xz reader: %v", err)
+	}
+
+	return extractTarStream(xzr, extractPath, opts)
+}
+
+// extractTarZst extracts a .tar.zst/.tzst file
+func extractTarZst(archivePath, extractPath string, opts ExtractOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %v", err)
+	}
+	defer file.Close()
+
+	zstr, err := zstd.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("error creating zstd reader: %v", err)
+	}
+	defer zstr.Close()
+
+	return extractTarStream(zstr, extractPath, opts)
+}
+
+// extractTar extracts a plain, uncompressed .tar file
+func extractTar(archivePath, extractPath string, opts ExtractOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %v", err)
+	}
+	defer file.Close()
+
+	return extractTarStream(file, extractPath, opts)
+}
+
+// extractTarStream reads tar entries from r and writes them under
+// extractPath, preserving each regular file's mode and mtime from the tar
+// header, rejecting entries (and symlink/hardlink targets) that escape
+// extractPath, and enforcing opts' size/entry limits
+func extractTarStream(r io.Reader, extractPath string, opts ExtractOptions) error {
+	tr := tar.NewReader(r)
+
+	var totalSize int64
+	var entries int
 
 	for {
 		header, err := tr.Next()
@@ -84,7 +230,15 @@ func extractTarGz(archivePath, extractPath string) error {
 			return fmt.Errorf("error reading tar: %v", err)
 		}
 
-		target := filepath.Join(extractPath, header.Name)
+		entries++
+		if entries > opts.MaxEntries {
+			return fmt.Errorf("archive has more than %d entries, refusing to extract", opts.MaxEntries)
+		}
+
+		target, err := safeJoin(extractPath, header.Name)
+		if err != nil {
+			return fmt.Errorf("error extracting %q: %v", header.Name, err)
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
@@ -96,7 +250,12 @@ func extractTarGz(archivePath, extractPath string) error {
 				return fmt.Errorf("error creating directory: %v", err)
 			}
 
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			totalSize += header.Size
+			if totalSize > opts.MaxDecompressedSize {
+				return fmt.Errorf("archive exceeds max decompressed size of %d bytes", opts.MaxDecompressedSize)
+			}
+
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_RDWR, os.FileMode(header.Mode))
 			if err != nil {
 				return fmt.Errorf("error creating file: %v", err)
 			}
@@ -106,69 +265,107 @@ func extractTarGz(archivePath, extractPath string) error {
 				return fmt.Errorf("error writing file: %v", err)
 			}
 			f.Close()
+			os.Chtimes(target, header.ModTime, header.ModTime)
+		case tar.TypeSymlink:
+			if err := safeSymlinkTarget(extractPath, filepath.Dir(target), header.Linkname); err != nil {
+				return fmt.Errorf("error extracting symlink %q: %v", header.Name, err)
+			}
+			os.Remove(target)
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return fmt.Errorf("error creating symlink: %v", err)
+			}
+		case tar.TypeLink:
+			linkTarget, err := safeJoin(extractPath, header.Linkname)
+			if err != nil {
+				return fmt.Errorf("error extracting hardlink %q: %v", header.Name, err)
+			}
+			os.Remove(target)
+			if err := os.Link(linkTarget, target); err != nil {
+				return fmt.Errorf("error creating hardlink: %v", err)
+			}
 		}
 	}
 
 	return nil
 }
 
-// extractTar extracts a .tar file
-func extractTar(archivePath, extractPath string) error {
-	file, err := os.Open(archivePath)
+// extract7z extracts a .7z file
+func extract7z(archivePath, extractPath string, opts ExtractOptions) error {
+	reader, err := sevenzip.OpenReader(archivePath)
 	if err != nil {
-		return fmt.Errorf("error opening archive: %v", err)
+		return fmt.Errorf("error opening 7z archive: %v", err)
 	}
-	defer file.Close()
+	defer reader.Close()
 
-	tr := tar.NewReader(file)
+	if len(reader.File) > opts.MaxEntries {
+		return fmt.Errorf("archive has more than %d entries, refusing to extract", opts.MaxEntries)
+	}
 
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
+	var totalSize int64
+	for _, file := range reader.File {
+		path, err := safeJoin(extractPath, file.Name)
 		if err != nil {
-			return fmt.Errorf("error reading tar: %v", err)
+			return fmt.Errorf("error extracting %q: %v", file.Name, err)
 		}
 
-		target := filepath.Join(extractPath, header.Name)
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if err := os.MkdirAll(target, 0755); err != nil {
-				return fmt.Errorf("error creating directory: %v", err)
-			}
-		case tar.TypeReg:
-			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
 				return fmt.Errorf("error creating directory: %v", err)
 			}
+			continue
+		}
 
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
-			if err != nil {
-				return fmt.Errorf("error creating file: %v", err)
-			}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("error creating directory: %v", err)
+		}
 
-			if _, err := io.Copy(f, tr); err != nil {
-				f.Close()
-				return fmt.Errorf("error writing file: %v", err)
-			}
-			f.Close()
+		totalSize += file.FileInfo().Size()
+		if totalSize > opts.MaxDecompressedSize {
+			return fmt.Errorf("archive exceeds max decompressed size of %d bytes", opts.MaxDecompressedSize)
 		}
+
+		fileReader, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("error opening file in 7z archive: %v", err)
+		}
+
+		targetFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
+		if err != nil {
+			fileReader.Close()
+			return fmt.Errorf("error creating file: %v", err)
+		}
+
+		if _, err := io.Copy(targetFile, fileReader); err != nil {
+			fileReader.Close()
+			targetFile.Close()
+			return fmt.Errorf("error writing file: %v", err)
+		}
+
+		fileReader.Close()
+		targetFile.Close()
 	}
 
 	return nil
 }
 
 // extractZip extracts a .zip file
-func extractZip(archivePath, extractPath string) error {
+func extractZip(archivePath, extractPath string, opts ExtractOptions) error {
 	reader, err := zip.OpenReader(archivePath)
 	if err != nil {
 		return fmt.Errorf("error opening zip: %v", err)
 	}
 	defer reader.Close()
 
+	if len(reader.File) > opts.MaxEntries {
+		return fmt.Errorf("archive has more than %d entries, refusing to extract", opts.MaxEntries)
+	}
+
+	var totalSize int64
 	for _, file := range reader.File {
-		path := filepath.Join(extractPath, file.Name)
+		path, err := safeJoin(extractPath, file.Name)
+		if err != nil {
+			return fmt.Errorf("error extracting %q: %v", file.Name, err)
+		}
 
 		if file.FileInfo().IsDir() {
 			if err := os.MkdirAll(path, 0755); err != nil {
@@ -186,6 +383,28 @@ func extractZip(archivePath, extractPath string) error {
 			return fmt.Errorf("error opening file in zip: %v", err)
 		}
 
+		if file.Mode()&os.ModeSymlink != 0 {
+			linkTarget, err := io.ReadAll(fileReader)
+			fileReader.Close()
+			if err != nil {
+				return fmt.Errorf("error reading symlink target: %v", err)
+			}
+			if err := safeSymlinkTarget(extractPath, filepath.Dir(path), string(linkTarget)); err != nil {
+				return fmt.Errorf("error extracting symlink %q: %v", file.Name, err)
+			}
+			os.Remove(path)
+			if err := os.Symlink(string(linkTarget), path); err != nil {
+				return fmt.Errorf("error creating symlink: %v", err)
+			}
+			continue
+		}
+
+		totalSize += int64(file.UncompressedSize64)
+		if totalSize > opts.MaxDecompressedSize {
+			fileReader.Close()
+			return fmt.Errorf("archive exceeds max decompressed size of %d bytes", opts.MaxDecompressedSize)
+		}
+
 		targetFile, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
 		if err != nil {
 			fileReader.Close()
@@ -200,6 +419,67 @@ func extractZip(archivePath, extractPath string) error {
 
 		fileReader.Close()
 		targetFile.Close()
+		os.Chtimes(path, file.Modified, file.Modified)
+	}
+
+	return nil
+}
+
+// extractSingleGz decompresses a plain .gz file (not a .tar.gz) to a file
+// named the same minus the .gz suffix, as GitHub releases sometimes ship a
+// single compressed binary this way
+func extractSingleGz(archivePath, extractPath string, opts ExtractOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %v", err)
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("error creating gzip reader: %v", err)
+	}
+	defer gzr.Close()
+
+	return extractSingleFile(gzr, extractPath, strings.TrimSuffix(filepath.Base(archivePath), ".gz"), opts)
+}
+
+// extractSingleXz decompresses a plain .xz file (not a .tar.xz) the same way
+func extractSingleXz(archivePath, extractPath string, opts ExtractOptions) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %v", err)
+	}
+	defer file.Close()
+
+	xzr, err := xz.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("error creating xz reader: %v", err)
+	}
+
+	return extractSingleFile(xzr, extractPath, strings.TrimSuffix(filepath.Base(archivePath), ".xz"), opts)
+}
+
+// extractSingleFile writes r's decompressed contents to name under
+// extractPath, enforcing opts.MaxDecompressedSize
+func extractSingleFile(r io.Reader, extractPath, name string, opts ExtractOptions) error {
+	target, err := safeJoin(extractPath, name)
+	if err != nil {
+		return fmt.Errorf("error extracting %q: %v", name, err)
+	}
+
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer f.Close()
+
+	written, err := io.CopyN(f, r, opts.MaxDecompressedSize+1)
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("error writing file: %v", err)
+	}
+	if written > opts.MaxDecompressedSize {
+		return fmt.Errorf("archive exceeds max decompressed size of %d bytes", opts.MaxDecompressedSize)
 	}
 
 	return nil
@@ -212,34 +492,66 @@ func Mkdir(path string) error {
 
 // GetSystemInfo returns information about the current system
 func GetSystemInfo() (string, string) {
-	os := runtime.GOOS
-	arch := runtime.GOARCH
+	return runtime.GOOS, normalizeArch(runtime.GOARCH)
+}
 
-	// Normalize architecture names
+// normalizeArch maps common Go/user-facing arch spellings onto the
+// canonical names archAliases and getPlatformPatterns key off of
+func normalizeArch(arch string) string {
 	switch arch {
 	case "amd64":
-		arch = "x86_64"
+		return "x86_64"
 	case "386":
-		arch = "i386"
+		return "i386"
 	case "arm64":
-		arch = "aarch64"
+		return "aarch64"
+	default:
+		return arch
+	}
+}
+
+// ExecutableExt returns the platform's executable file extension, ".exe" on
+// Windows and "" everywhere else
+func ExecutableExt() string {
+	if runtime.GOOS == "windows" {
+		return ".exe"
 	}
+	return ""
+}
 
-	return os, arch
+// NormalizeBinaryName strips the platform executable extension from name, so
+// a tool installed on Windows and upgraded on Linux (or vice versa) is
+// tracked under the same state key instead of "tool" and "tool.exe"
+func NormalizeBinaryName(name string) string {
+	if runtime.GOOS == "windows" && strings.EqualFold(filepath.Ext(name), ".exe") {
+		return strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
 }
 
-// IsExecutable checks if a file is executable
+// IsExecutable checks if a file is executable. NTFS doesn't carry the Unix
+// executable permission bit, so on Windows any .exe is treated as executable
 func IsExecutable(path string) bool {
 	info, err := os.Stat(path)
 	if err != nil {
 		return false
 	}
+	if !info.Mode().IsRegular() {
+		return false
+	}
 
-	return info.Mode().IsRegular() && (info.Mode()&0111) != 0
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return (info.Mode() & 0111) != 0
 }
 
 // IsBinaryExecutable checks if a file is a binary executable by reading its header
 func IsBinaryExecutable(path string) bool {
+	if runtime.GOOS == "windows" && strings.EqualFold(filepath.Ext(path), ".exe") {
+		return true
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return false
@@ -454,6 +766,30 @@ func CopyFile(src, dst string) error {
 	return nil
 }
 
+// namedAssetCopy symlinks src into a fresh temp directory under name,
+// falling back to a full copy if symlinking isn't possible (e.g. Windows
+// without the privilege), so callers that dispatch on file extension (like
+// Extract) see the asset's real filename instead of an arbitrary src path.
+// The returned cleanup func removes the temp directory and must be called
+// once the caller is done with the named path.
+func namedAssetCopy(src, name string) (path string, cleanup func(), err error) {
+	dir, err := os.MkdirTemp("", "install-release-asset-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating temp directory: %v", err)
+	}
+	cleanup = func() { RemoveDir(dir) }
+
+	namedPath := filepath.Join(dir, name)
+	if err := os.Symlink(src, namedPath); err != nil {
+		if err := CopyFile(src, namedPath); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("error copying asset: %v", err)
+		}
+	}
+
+	return namedPath, cleanup, nil
+}
+
 // RemoveFile removes a file
 func RemoveFile(path string) error {
 	return os.Remove(path)
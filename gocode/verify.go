@@ -0,0 +1,275 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyOptions controls how a downloaded asset is verified before extraction
+type VerifyOptions struct {
+	Skip             bool
+	MinisignPubKey   string
+	CosignPubKey     string
+	RequireSignature bool
+}
+
+// checksumManifestNames are well known checksum manifest filenames, checked
+// in order against a release's asset list
+var checksumManifestNames = []string{"SHA256SUMS", "checksums.txt"}
+
+// signatureBackend is a pluggable signature-verification scheme, identified
+// by the extension its signature file is published under. Add a new scheme
+// by appending to signatureBackends and giving it a pub-key field in
+// VerifyOptions and pubKeyFor.
+type signatureBackend struct {
+	name   string
+	ext    string
+	verify func(downloadPath, sigPath, pubKey string) error
+}
+
+var signatureBackends = []signatureBackend{
+	{
+		name: "minisign",
+		ext:  ".minisig",
+		verify: func(downloadPath, sigPath, pubKey string) error {
+			return RunCommand("minisign", "-V", "-P", pubKey, "-m", downloadPath, "-x", sigPath)
+		},
+	},
+	{
+		name: "cosign",
+		ext:  ".sig",
+		verify: func(downloadPath, sigPath, pubKey string) error {
+			return RunCommand("cosign", "verify-blob", "--key", pubKey, "--signature", sigPath, downloadPath)
+		},
+	},
+}
+
+// pubKeyFor returns the configured public key for a signature backend
+func (opts VerifyOptions) pubKeyFor(backend string) string {
+	switch backend {
+	case "minisign":
+		return opts.MinisignPubKey
+	case "cosign":
+		return opts.CosignPubKey
+	default:
+		return ""
+	}
+}
+
+// ResolveVerifyOptions builds VerifyOptions for a repo URL. When no global
+// minisign/cosign key is configured, it falls back to a key pinned for that
+// repo via `ir config --trusted-key`
+func ResolveVerifyOptions(config *ConfigManager, repoURL string, skip bool) VerifyOptions {
+	minisignKey := config.GetMinisignPubKey()
+	cosignKey := config.GetCosignPubKey()
+	if minisignKey == "" && cosignKey == "" {
+		if trusted := config.GetTrustedKey(repoURL); trusted != "" {
+			minisignKey = trusted
+			cosignKey = trusted
+		}
+	}
+	return VerifyOptions{
+		Skip:             skip,
+		MinisignPubKey:   minisignKey,
+		CosignPubKey:     cosignKey,
+		RequireSignature: config.GetRequireSignature(),
+	}
+}
+
+// VerifyAsset checks the downloaded file at downloadPath against any
+// checksum manifest or per-asset checksum file published alongside asset in
+// release.Assets, and, if configured, verifies a minisign or cosign
+// signature over the file. It returns the file's sha256 digest so callers
+// can record it in state and detect a tampered re-download of the same tag
+func VerifyAsset(asset *ReleaseAssets, release *Release, downloadPath string, opts VerifyOptions) (string, error) {
+	if opts.Skip {
+		return "", nil
+	}
+
+	digest, err := sha256File(downloadPath)
+	if err != nil {
+		return "", fmt.Errorf("error hashing downloaded file: %v", err)
+	}
+
+	if checksumFile := findChecksumAsset(asset, release); checksumFile != nil {
+		if err := verifyChecksum(asset, checksumFile, digest); err != nil {
+			return "", err
+		}
+	}
+
+	sigFile, backend := findSignatureAsset(asset, release)
+	if sigFile == nil {
+		if opts.RequireSignature {
+			return "", fmt.Errorf("no signature found for %s and require-signature is enabled", asset.Name)
+		}
+		return digest, nil
+	}
+
+	pubKey := opts.pubKeyFor(backend.name)
+	if pubKey == "" {
+		if opts.RequireSignature {
+			return "", fmt.Errorf("no %s public key configured to verify %s and require-signature is enabled", backend.name, asset.Name)
+		}
+		return digest, nil
+	}
+
+	if err := verifySignature(backend, downloadPath, sigFile, pubKey); err != nil {
+		return "", err
+	}
+
+	return digest, nil
+}
+
+// CheckTamper compares a freshly verified digest against the digest recorded
+// the last time the same tag was installed, so a re-download whose asset
+// changed under an unchanged tag is reported rather than silently installed
+func CheckTamper(previous *Release, newTagName, newDigest string) error {
+	if previous == nil || previous.VerifiedDigest == "" || newDigest == "" {
+		return nil
+	}
+	if previous.TagName == newTagName && previous.VerifiedDigest != newDigest {
+		return fmt.Errorf("digest for %s changed since it was last installed (%s => %s) despite an unchanged tag - possible tampering, rerun with --no-verify to override", newTagName, previous.VerifiedDigest, newDigest)
+	}
+	return nil
+}
+
+// findChecksumAsset looks for a checksum manifest or a per-asset checksum
+// file among the release's other assets
+func findChecksumAsset(asset *ReleaseAssets, release *Release) *ReleaseAssets {
+	perAsset := asset.Name + ".sha256"
+
+	for i := range release.Assets {
+		candidate := &release.Assets[i]
+		if candidate.Name == perAsset {
+			return candidate
+		}
+	}
+
+	for _, manifestName := range checksumManifestNames {
+		for i := range release.Assets {
+			candidate := &release.Assets[i]
+			if candidate.Name == manifestName {
+				return candidate
+			}
+		}
+	}
+
+	return nil
+}
+
+// findSignatureAsset looks for a signature file matching one of
+// signatureBackends for asset, returning the asset and the backend it
+// belongs to
+func findSignatureAsset(asset *ReleaseAssets, release *Release) (*ReleaseAssets, signatureBackend) {
+	for i := range release.Assets {
+		candidate := &release.Assets[i]
+		for _, backend := range signatureBackends {
+			if candidate.Name == asset.Name+backend.ext {
+				return candidate, backend
+			}
+		}
+	}
+	return nil, signatureBackend{}
+}
+
+// verifyChecksum downloads the checksum manifest and compares its entry for
+// asset against the already-computed digest of the downloaded file
+func verifyChecksum(asset *ReleaseAssets, checksumAsset *ReleaseAssets, digest string) error {
+	expected, err := downloadChecksumEntry(checksumAsset, asset.Name)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(digest, expected) {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", asset.Name, expected, digest)
+	}
+
+	return nil
+}
+
+// downloadChecksumEntry downloads checksumAsset's manifest and returns the
+// expected digest for assetName, letting callers (e.g. ExtractRelease) learn
+// an asset's expected digest before downloading it, for content-addressed
+// caching
+func downloadChecksumEntry(checksumAsset *ReleaseAssets, assetName string) (string, error) {
+	tempDir := filepath.Join(GetTempDir(), "install-release", "verify")
+	if err := Mkdir(tempDir); err != nil {
+		return "", fmt.Errorf("error creating verify temp directory: %v", err)
+	}
+
+	manifestPath := filepath.Join(tempDir, checksumAsset.Name)
+	if err := Download(checksumAsset.BrowserDownloadURL, manifestPath); err != nil {
+		return "", fmt.Errorf("error downloading checksum manifest: %v", err)
+	}
+	defer RemoveFile(manifestPath)
+
+	manifest, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading checksum manifest: %v", err)
+	}
+
+	return parseChecksumManifest(string(manifest), assetName)
+}
+
+// parseChecksumManifest parses the standard "<hex>  <filename>" format used
+// by SHA256SUMS/checksums.txt and returns the hash for assetName, or a
+// per-asset "<hex>  <filename>" / bare-hash file
+func parseChecksumManifest(manifest, assetName string) (string, error) {
+	for _, line := range strings.Split(manifest, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 1 {
+			// Per-asset checksum file containing only the hex digest
+			return fields[0], nil
+		}
+		if len(fields) >= 2 {
+			// Some tools prefix the filename with "*" to mark binary mode
+			name := strings.TrimPrefix(fields[len(fields)-1], "*")
+			if name == assetName || strings.HasSuffix(name, "/"+assetName) {
+				return fields[0], nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no checksum entry found for %s", assetName)
+}
+
+// sha256File returns the hex-encoded sha256 digest of the file at path
+func sha256File(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// verifySignature downloads sigAsset and runs backend's verifier over it
+func verifySignature(backend signatureBackend, downloadPath string, sigAsset *ReleaseAssets, pubKey string) error {
+	sigPath := downloadPath + backend.ext
+	if err := Download(sigAsset.BrowserDownloadURL, sigPath); err != nil {
+		return fmt.Errorf("error downloading %s signature: %v", backend.name, err)
+	}
+	defer RemoveFile(sigPath)
+
+	if err := backend.verify(downloadPath, sigPath, pubKey); err != nil {
+		return fmt.Errorf("%s signature verification failed: %v", backend.name, err)
+	}
+
+	return nil
+}